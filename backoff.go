@@ -17,6 +17,20 @@ const (
 	DefaultBackoffMax = 100 * time.Millisecond
 )
 
+// BackoffMode selects how Backoff computes successive sleep durations.
+type BackoffMode uint8
+
+const (
+	// BackoffLinear is the default block-based linear curve with ±12.5% jitter.
+	BackoffLinear BackoffMode = iota
+
+	// BackoffDecorrelated uses "decorrelated jitter":
+	// sleep = min(max, random_between(base, prev*3)), with prev starting at base.
+	// Successive sleeps wander instead of following a shared curve, which
+	// desynchronizes clients that started backing off at the same moment.
+	BackoffDecorrelated
+)
+
 // Backoff implements a linear block-based back-off strategy with jitter.
 // It is designed for external I/O readiness waiting (e.g., buffer release).
 //
@@ -34,9 +48,15 @@ const (
 type Backoff struct {
 	n       int           // block counter (1-indexed)
 	i       int           // iteration within current block
+	waits   int           // completed waits since the last Reset
 	base    time.Duration // base duration
 	max     time.Duration // maximum duration
 	fastSrc uint64        // PRNG state for jitter
+	sleep   func(time.Duration)
+	prev    time.Duration // last decorrelated sleep (BackoffDecorrelated)
+	mode    BackoffMode
+	fixed   bool // constant mode: never advance past block 1
+	exact   bool // jitter disabled by SetJitter(false)
 }
 
 // Wait performs a non-blocking-friendly sleep.
@@ -55,14 +75,29 @@ func (b *Backoff) Wait() {
 		}
 	}
 
-	// Linear duration: base * n
-	d := time.Duration(b.n) * b.base
-	if d > b.max {
-		d = b.max
+	var d time.Duration
+	if b.mode == BackoffDecorrelated {
+		d = b.nextDecorrelated()
+	} else {
+		// Linear duration: base * n
+		d = time.Duration(b.n) * b.base
+		if d > b.max {
+			d = b.max
+		}
+		if !b.exact {
+			d = b.applyJitter(d)
+		}
 	}
+	if b.sleep != nil {
+		b.sleep(d)
+	} else {
+		time.Sleep(d)
+	}
+	b.waits++
 
-	time.Sleep(b.applyJitter(d))
-
+	if b.fixed {
+		return
+	}
 	b.i++
 	if b.i >= b.n {
 		b.i = 0
@@ -79,14 +114,76 @@ func (b *Backoff) applyJitter(d time.Duration) time.Duration {
 	return d + time.Duration(factor)
 }
 
+func (b *Backoff) nextDecorrelated() time.Duration {
+	prev := b.prev
+	if prev < b.base {
+		prev = b.base
+	}
+	hi := prev * 3
+	if hi > b.max {
+		hi = b.max
+	}
+	d := b.base
+	if b.exact {
+		d = hi
+	} else if hi > b.base {
+		b.fastSrc ^= b.fastSrc << 13
+		b.fastSrc ^= b.fastSrc >> 7
+		b.fastSrc ^= b.fastSrc << 17
+		d += time.Duration(b.fastSrc % uint64(hi-b.base+1))
+	}
+	if d > b.max {
+		d = b.max
+	}
+	b.prev = d
+	return d
+}
+
 // SetBase configures the initial duration and linear scaling factor.
 func (b *Backoff) SetBase(d time.Duration) { b.base = d }
 
 // SetMax configures the maximum allowed sleep duration.
 func (b *Backoff) SetMax(d time.Duration) { b.max = d }
 
+// SetConstant pins the backoff to a fixed duration d. Every Wait sleeps for
+// d ± 12.5% jitter, the block never advances, and Block always returns 1.
+// A d <= 0 selects DefaultBackoffBase. Reset keeps the constant mode.
+func (b *Backoff) SetConstant(d time.Duration) {
+	if d <= 0 {
+		d = DefaultBackoffBase
+	}
+	b.base, b.max, b.fixed = d, d, true
+	b.mode = BackoffLinear
+}
+
+// SetMode selects the duration algorithm. It clears constant mode set by
+// SetConstant. In BackoffDecorrelated mode, Duration returns the last
+// computed sleep (base before the first Wait) and each Wait advances it.
+func (b *Backoff) SetMode(m BackoffMode) {
+	b.mode = m
+	b.fixed = false
+}
+
+// SetJitter enables or disables jitter; it is enabled by default. With jitter
+// disabled, linear and constant modes sleep exactly min(base × n, max), and
+// BackoffDecorrelated always picks the top of its range, min(max, prev×3).
+// Reset keeps the setting.
+func (b *Backoff) SetJitter(on bool) { b.exact = !on }
+
+// SetSleepFunc replaces the function Wait uses to sleep. It receives the
+// jittered duration, or the exact one after SetJitter(false). A nil f
+// restores the default, time.Sleep.
+//
+// This is primarily for tests that substitute a fake clock, but it can also
+// route waits through a runtime-specific timer.
+func (b *Backoff) SetSleepFunc(f func(time.Duration)) { b.sleep = f }
+
 // Reset restores the backoff state to block 1.
-func (b *Backoff) Reset() { b.n = 0; b.i = 0 }
+func (b *Backoff) Reset() { b.n = 0; b.i = 0; b.waits = 0; b.prev = 0 }
+
+// Attempts returns the number of completed Wait calls since the last Reset.
+// Unlike Block, which reports the current tier, it counts every sleep.
+func (b *Backoff) Attempts() int { return b.waits }
 
 // Block returns the current progression tier.
 func (b *Backoff) Block() int {
@@ -98,7 +195,11 @@ func (b *Backoff) Block() int {
 
 // Duration returns the current duration without jitter.
 // For a zero-value Backoff, returns DefaultBackoffBase.
+// In BackoffDecorrelated mode it returns the last computed sleep instead.
 func (b *Backoff) Duration() time.Duration {
+	if b.mode == BackoffDecorrelated && b.prev > 0 {
+		return b.prev
+	}
 	n := b.n
 	if n == 0 {
 		n = 1
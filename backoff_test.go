@@ -211,3 +211,191 @@ func TestBackoff_WaitMaxCap(t *testing.T) {
 		t.Errorf("After capped Wait(), Block() = %d, want 2", got)
 	}
 }
+
+func TestBackoff_SetSleepFunc_RecordsSequence(t *testing.T) {
+	var b iox.Backoff
+	base := time.Millisecond
+	b.SetBase(base)
+	b.SetMax(4 * base)
+	b.SetJitter(false)
+
+	var slept []time.Duration
+	b.SetSleepFunc(func(d time.Duration) { slept = append(slept, d) })
+
+	// Blocks 1..5 with the 4ms cap: 1, 2, 2, 3, 3, 3, 4, 4, 4, 4, 4 (ms).
+	want := []time.Duration{1, 2, 2, 3, 3, 3, 4, 4, 4, 4, 4}
+	start := time.Now()
+	for i, w := range want {
+		if got := b.Duration(); got != w*base {
+			t.Fatalf("wait %d: Duration()=%v want %v", i, got, w*base)
+		}
+		b.Wait()
+	}
+	if elapsed := time.Since(start); elapsed > 10*base {
+		t.Fatalf("fake sleep should not block, elapsed=%v", elapsed)
+	}
+	if len(slept) != len(want) {
+		t.Fatalf("recorded %d sleeps, want %d", len(slept), len(want))
+	}
+	for i, w := range want {
+		if slept[i] != w*base {
+			t.Fatalf("sleep %d = %v want %v", i, slept[i], w*base)
+		}
+	}
+}
+
+func TestBackoff_SetJitter_OnByDefault(t *testing.T) {
+	const d = 8 * time.Millisecond
+	var b iox.Backoff
+	var slept []time.Duration
+	b.SetSleepFunc(func(x time.Duration) { slept = append(slept, x) })
+	b.SetConstant(d)
+	for i := 0; i < 50; i++ {
+		b.Wait()
+	}
+	jittered := false
+	for i, x := range slept {
+		if x < d-d/8 || x > d+d/8 {
+			t.Fatalf("sleep %d: %v outside %v ±12.5%%", i, x, d)
+		}
+		if x != d {
+			jittered = true
+		}
+	}
+	if !jittered {
+		t.Fatal("default Backoff applied no jitter")
+	}
+}
+
+func TestBackoff_SetSleepFunc_NilRestoresDefault(t *testing.T) {
+	var b iox.Backoff
+	b.SetBase(50 * time.Microsecond)
+	calls := 0
+	b.SetSleepFunc(func(time.Duration) { calls++ })
+	b.Wait()
+	b.SetSleepFunc(nil)
+	b.Wait()
+	if calls != 1 {
+		t.Fatalf("calls=%d want 1", calls)
+	}
+}
+
+func TestBackoff_Attempts(t *testing.T) {
+	var b iox.Backoff
+	b.SetSleepFunc(func(time.Duration) {})
+	if b.Attempts() != 0 {
+		t.Fatalf("zero-value Attempts()=%d", b.Attempts())
+	}
+	// 7 waits cross blocks 1, 2, 3 and into 4.
+	for i := 1; i <= 7; i++ {
+		b.Wait()
+		if got := b.Attempts(); got != i {
+			t.Fatalf("after %d waits Attempts()=%d", i, got)
+		}
+	}
+	if b.Block() != 4 {
+		t.Fatalf("Block()=%d want 4", b.Block())
+	}
+	b.Reset()
+	if b.Attempts() != 0 || b.Block() != 1 {
+		t.Fatalf("after Reset: Attempts()=%d Block()=%d", b.Attempts(), b.Block())
+	}
+}
+
+func TestBackoff_SetConstant(t *testing.T) {
+	const d = 8 * time.Millisecond
+	var b iox.Backoff
+	var slept []time.Duration
+	b.SetSleepFunc(func(x time.Duration) { slept = append(slept, x) })
+	b.SetConstant(d)
+	b.SetJitter(false)
+
+	for i := 0; i < 50; i++ {
+		b.Wait()
+		if b.Block() != 1 {
+			t.Fatalf("wait %d: Block()=%d want 1", i, b.Block())
+		}
+		if b.Duration() != d {
+			t.Fatalf("wait %d: Duration()=%v want %v", i, b.Duration(), d)
+		}
+	}
+	for i, x := range slept {
+		if x != d {
+			t.Fatalf("sleep %d: %v want %v", i, x, d)
+		}
+	}
+
+	b.Reset()
+	b.Wait()
+	if b.Block() != 1 || b.Duration() != d || slept[len(slept)-1] != d {
+		t.Fatalf("after Reset: Block()=%d Duration()=%v", b.Block(), b.Duration())
+	}
+}
+
+func TestBackoff_Decorrelated(t *testing.T) {
+	const base, max = time.Millisecond, 50 * time.Millisecond
+	var b iox.Backoff
+	var slept []time.Duration
+	b.SetSleepFunc(func(x time.Duration) { slept = append(slept, x) })
+	b.SetBase(base)
+	b.SetMax(max)
+	b.SetMode(iox.BackoffDecorrelated)
+
+	if b.Duration() != base {
+		t.Fatalf("initial Duration()=%v want %v", b.Duration(), base)
+	}
+	for i := 0; i < 200; i++ {
+		b.Wait()
+		if b.Duration() != slept[i] {
+			t.Fatalf("wait %d: Duration()=%v want last sleep %v", i, b.Duration(), slept[i])
+		}
+	}
+	decreased := false
+	for i, x := range slept {
+		if x < base || x > max {
+			t.Fatalf("sleep %d: %v outside [%v, %v]", i, x, base, max)
+		}
+		if i > 0 && x < slept[i-1] {
+			decreased = true
+		}
+	}
+	if !decreased {
+		t.Fatal("decorrelated sleeps were strictly non-decreasing")
+	}
+
+	b.Reset()
+	if b.Duration() != base {
+		t.Fatalf("after Reset: Duration()=%v want %v", b.Duration(), base)
+	}
+}
+
+func TestBackoff_DecorrelatedWithoutJitter(t *testing.T) {
+	const base, max = time.Millisecond, 50 * time.Millisecond
+	var b iox.Backoff
+	var slept []time.Duration
+	b.SetSleepFunc(func(x time.Duration) { slept = append(slept, x) })
+	b.SetBase(base)
+	b.SetMax(max)
+	b.SetMode(iox.BackoffDecorrelated)
+	b.SetJitter(false)
+
+	// Each sleep is the top of the range: min(max, prev*3).
+	want := []time.Duration{3 * base, 9 * base, 27 * base, max, max}
+	for i, w := range want {
+		b.Wait()
+		if b.Duration() != w {
+			t.Fatalf("wait %d: Duration()=%v want %v", i, b.Duration(), w)
+		}
+	}
+	for i, w := range want {
+		if slept[i] != w {
+			t.Fatalf("sleep %d = %v want %v", i, slept[i], w)
+		}
+	}
+
+	b.Reset()
+	b.Wait()
+	if slept[len(slept)-1] != 3*base {
+		t.Fatalf("after Reset: sleep=%v want %v", slept[len(slept)-1], 3*base)
+	}
+}
@@ -127,6 +127,31 @@ func BenchmarkCopy_WriterTo(b *testing.B) {
 	}
 }
 
+// BenchmarkCopier_SmallWriterTo compares repeated tiny WriterTo copies
+// between the same endpoints through Copy and through a reused Copier.
+func BenchmarkCopier_SmallWriterTo(b *testing.B) {
+	var src iox.Reader = benchWT{buf: bytes.Repeat([]byte{'x'}, 16)}
+	b.Run("Copy", func(b *testing.B) {
+		b.SetBytes(16)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := iox.Copy(devNull{}, src); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Copier", func(b *testing.B) {
+		c := iox.NewCopier(devNull{}, src)
+		b.SetBytes(16)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := c.Copy(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
 func BenchmarkCopy_ReaderFrom(b *testing.B) {
 	sizes := []int{1 << 10, 32 << 10, 1 << 20}
 	for _, size := range sizes {
@@ -163,6 +188,50 @@ func BenchmarkCopyN(b *testing.B) {
 	}
 }
 
+// BenchmarkCopyN_SlowPath hides WriterTo so CopyN stages through its buffer,
+// for comparison with BenchmarkCopyN, which takes the WriterTo fast path.
+func BenchmarkCopyN_SlowPath(b *testing.B) {
+	sizes := []int{1 << 10, 32 << 10, 1 << 20}
+	for _, size := range sizes {
+		b.Run(byteSize(size), func(b *testing.B) {
+			data := bytes.Repeat([]byte{'x'}, size)
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				src := struct{ io.Reader }{bytes.NewReader(data)}
+				_, err := iox.CopyN(devNull{}, src, int64(size))
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkCopyNPolicyPooled compares the pooled staging buffer with
+// CopyNPolicy's per-call buffer on the policy-aware read/write loop.
+func BenchmarkCopyNPolicyPooled(b *testing.B) {
+	const size = 4 << 10
+	data := bytes.Repeat([]byte{'x'}, size)
+	copies := map[string]func(iox.Writer, iox.Reader, int64, iox.SemanticPolicy) (int64, error){
+		"CopyNPolicy": iox.CopyNPolicy,
+		"Pooled":      iox.CopyNPolicyPooled,
+	}
+	for _, name := range []string{"CopyNPolicy", "Pooled"} {
+		copyN := copies[name]
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(size)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				src := struct{ io.Reader }{bytes.NewReader(data)}
+				if _, err := copyN(devNull{}, src, size, iox.YieldPolicy{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
 func BenchmarkCopyNBuffer(b *testing.B) {
 	sizes := []int{1 << 10, 32 << 10, 1 << 20}
 	for _, size := range sizes {
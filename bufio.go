@@ -0,0 +1,393 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iox
+
+import (
+	"bytes"
+	"io"
+)
+
+// BufWriter is a bounded buffered Writer for non-blocking destinations.
+//
+// Writes are absorbed into a fixed-size buffer. When the buffer is full, the
+// writer makes one attempt to flush it to the underlying Writer; if that
+// attempt would block, Write returns the bytes accepted so far together with
+// ErrWouldBlock (backpressure). Call Flush once the underlying Writer is ready
+// to drain the buffer.
+//
+// BufWriter implements ReaderFrom so Copy can read directly into the buffer
+// without an intermediate staging copy. No bytes are ever read from the
+// source unless there is room to keep them.
+type BufWriter struct {
+	w   Writer
+	buf []byte // buffered bytes; cap(buf) is the buffer size
+}
+
+// NewBufWriter returns a BufWriter writing to w with a buffer of size bytes.
+// If size <= 0, len(Buffer) is used.
+func NewBufWriter(w Writer, size int) *BufWriter {
+	if size <= 0 {
+		size = len(Buffer{})
+	}
+	return &BufWriter{w: w, buf: make([]byte, 0, size)}
+}
+
+// Size returns the size of the underlying buffer in bytes.
+func (b *BufWriter) Size() int { return cap(b.buf) }
+
+// Buffered returns the number of bytes waiting to be flushed.
+func (b *BufWriter) Buffered() int { return len(b.buf) }
+
+// Available returns how many bytes can be written without flushing.
+func (b *BufWriter) Available() int { return cap(b.buf) - len(b.buf) }
+
+// Write appends p to the buffer.
+//
+// If p does not fit, Write attempts to Flush; when the flush returns an error
+// (typically ErrWouldBlock), Write returns (n, err) where n is the number of
+// bytes of p that were buffered. Retry with p[n:] after the destination
+// becomes writable.
+func (b *BufWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		if b.Available() == 0 {
+			if err = b.Flush(); err != nil {
+				return n, err
+			}
+		}
+		m := copy(b.buf[len(b.buf):cap(b.buf)], p)
+		b.buf = b.buf[:len(b.buf)+m]
+		n += m
+		p = p[m:]
+	}
+	return n, nil
+}
+
+// Flush writes buffered bytes to the underlying Writer.
+//
+// On a partial flush, the written prefix is discarded from the buffer and the
+// underlying error (including ErrWouldBlock / ErrMore) is returned unchanged;
+// the remaining bytes stay buffered for the next Flush. A (0, nil) write is
+// reported as io.ErrShortWrite.
+func (b *BufWriter) Flush() error {
+	for len(b.buf) > 0 {
+		n, err := b.w.Write(b.buf)
+		if n > 0 {
+			b.buf = b.buf[:copy(b.buf, b.buf[n:])]
+		}
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return io.ErrShortWrite
+		}
+	}
+	return nil
+}
+
+// ReadFrom reads from r directly into the buffer until EOF, an error, or a
+// (0, nil) read.
+//
+// When the buffer fills up, ReadFrom attempts to Flush; a flush error
+// (typically ErrWouldBlock) is returned with the bytes read so far. Read-side
+// ErrWouldBlock / ErrMore from r are returned unchanged. io.EOF maps to nil.
+func (b *BufWriter) ReadFrom(r Reader) (n int64, err error) {
+	for {
+		if b.Available() == 0 {
+			if err = b.Flush(); err != nil {
+				return n, err
+			}
+		}
+		m, er := r.Read(b.buf[len(b.buf):cap(b.buf)])
+		if m > 0 {
+			b.buf = b.buf[:len(b.buf)+m]
+			n += int64(m)
+		}
+		if er != nil {
+			if er == io.EOF {
+				return n, nil
+			}
+			return n, er
+		}
+		if m == 0 {
+			return n, nil
+		}
+	}
+}
+
+// FlushWriter is a Writer that buffers and drains on Flush, such as
+// *BufWriter or *gzip.Writer.
+//
+// A non-blocking FlushWriter should report a partial flush with ErrWouldBlock
+// (or ErrMore) and keep the unwritten bytes for the next Flush, rather than
+// failing with io.ErrShortWrite. *bufio.Writer does not qualify: it keeps the
+// first write error, so after an ErrWouldBlock every later Write and Flush
+// fails. Use NewFlushWriter (a BufWriter) for a retry-safe buffered writer.
+type FlushWriter interface {
+	Writer
+	Flush() error
+}
+
+// Flush flushes w if it implements FlushWriter and returns nil otherwise.
+//
+// ErrWouldBlock and ErrMore from w.Flush are returned unchanged so the caller
+// can retry once the destination is writable; raw EAGAIN / EWOULDBLOCK is
+// reported as ErrWouldBlock. Other errors are returned as is.
+func Flush(w Writer) error {
+	fw, ok := w.(FlushWriter)
+	if !ok {
+		return nil
+	}
+	return mapEAGAIN(fw.Flush())
+}
+
+// NewFlushWriter returns a FlushWriter that buffers up to size bytes for conn.
+// If size <= 0, len(Buffer) is used.
+//
+// It is a BufWriter: Write applies backpressure with ErrWouldBlock when the
+// buffer is full, and a Flush interrupted by ErrWouldBlock / ErrMore keeps
+// the flushed prefix written and the remainder buffered.
+func NewFlushWriter(conn Writer, size int) FlushWriter {
+	return NewBufWriter(conn, size)
+}
+
+// AtomicFrameWriter returns a FlushWriter for message-oriented sinks that
+// treats each Write(p) as one frame: callers see either the whole frame
+// accepted or none of it.
+//
+// If w accepts only a prefix of p and reports ErrWouldBlock (or ErrMore),
+// the prefix is already committed downstream, so the wrapper keeps a copy of
+// the frame and returns (0, err). As with any (0, err) result, the caller
+// retries the same p, as Copy and the policy engines do; a Write with the
+// same bytes is taken as that retry, sends the unsent remainder instead of
+// p, and reports (len(p), nil). Any other p first completes the pending frame
+// and is then written as a frame of its own. Flush also completes the pending
+// frame; once it returns nil the frame is done and must not be retried. While
+// the remainder cannot be sent, Write and Flush return (0, ErrWouldBlock).
+//
+// Only a single frame is ever buffered, so memory is bounded by the largest
+// frame. A semantic error with nothing accepted is returned as (0, err)
+// without buffering; retry the same p. Other errors are returned with the
+// bytes accepted from p, since a failed sink cannot be made atomic.
+func AtomicFrameWriter(w Writer) FlushWriter { return &atomicFrameWriter{w: w} }
+
+type atomicFrameWriter struct {
+	w     Writer
+	frame []byte // last partially accepted frame; nil if none
+	sent  int    // bytes of frame already accepted by w
+}
+
+func (a *atomicFrameWriter) Write(p []byte) (n int, err error) {
+	if a.frame != nil {
+		retry := bytes.Equal(p, a.frame)
+		if err = a.Flush(); err != nil {
+			return 0, err
+		}
+		if retry {
+			return len(p), nil
+		}
+	}
+	for n < len(p) {
+		m, ew := a.w.Write(p[n:])
+		n += m
+		if ew != nil {
+			if n > 0 && n < len(p) && IsSemantic(ew) {
+				a.frame = append(a.frame[:0], p...)
+				a.sent = n
+				return 0, ew
+			}
+			return n, ew
+		}
+		if m == 0 {
+			return n, io.ErrShortWrite
+		}
+	}
+	return n, nil
+}
+
+// Flush sends the unsent remainder of a partially accepted frame, if any.
+func (a *atomicFrameWriter) Flush() error {
+	for a.frame != nil {
+		m, err := a.w.Write(a.frame[a.sent:])
+		a.sent += m
+		if a.sent == len(a.frame) {
+			a.frame, a.sent = nil, 0
+		}
+		if err != nil {
+			return err
+		}
+		if m == 0 && a.frame != nil {
+			return io.ErrShortWrite
+		}
+	}
+	return nil
+}
+
+// BufReader is a bounded buffered Reader for non-blocking sources.
+//
+// It reads ahead from the underlying Reader into a fixed-size buffer and
+// serves Read calls from the buffer, calling the underlying Reader only when
+// the buffer is empty. Semantics of the underlying Reader are preserved:
+//   - ErrWouldBlock with no buffered data is returned as (0, ErrWouldBlock).
+//   - A (0, nil) read is returned as (0, nil), not as EOF.
+//   - An error returned together with data (e.g., ErrMore or EOF) is held
+//     back and delivered with the last buffered byte of that completion.
+//
+// BufReader implements WriterTo so Copy can drain the buffer and the source
+// without an intermediate staging copy. Bytes that the destination does not
+// accept remain buffered, so a semantic stop never loses data.
+type BufReader struct {
+	r   Reader
+	buf []byte
+	rd  int   // read position in buf
+	wr  int   // write position in buf
+	err error // pending error from the last fill
+}
+
+// NewBufReader returns a BufReader reading from r with a buffer of size
+// bytes. If size <= 0, len(Buffer) is used.
+func NewBufReader(r Reader, size int) *BufReader {
+	if size <= 0 {
+		size = len(Buffer{})
+	}
+	return &BufReader{r: r, buf: make([]byte, size)}
+}
+
+// Size returns the size of the underlying buffer in bytes.
+func (b *BufReader) Size() int { return len(b.buf) }
+
+// Buffered returns the number of bytes that can be read from the buffer
+// without calling the underlying Reader.
+func (b *BufReader) Buffered() int { return b.wr - b.rd }
+
+// fill performs one read into the empty buffer. It reports false if the
+// underlying Reader returned (0, nil).
+func (b *BufReader) fill() bool {
+	n, err := b.r.Read(b.buf)
+	b.rd, b.wr = 0, n
+	b.err = err
+	return n > 0 || err != nil
+}
+
+// takeErr returns and clears the pending error.
+func (b *BufReader) takeErr() error {
+	err := b.err
+	b.err = nil
+	return err
+}
+
+// Read reads data into p from the buffer, refilling it from the underlying
+// Reader only when it is empty.
+func (b *BufReader) Read(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		if b.Buffered() > 0 {
+			return 0, nil
+		}
+		return 0, b.takeErr()
+	}
+	if b.rd == b.wr {
+		if b.err != nil {
+			return 0, b.takeErr()
+		}
+		if !b.fill() {
+			return 0, nil
+		}
+	}
+	n = copy(p, b.buf[b.rd:b.wr])
+	b.rd += n
+	if b.rd == b.wr {
+		return n, b.takeErr()
+	}
+	return n, nil
+}
+
+// WriteTo writes buffered data and then the rest of the underlying Reader to
+// w until EOF, an error, or a (0, nil) read. io.EOF maps to nil.
+//
+// Semantic errors from either side are returned unchanged with the number of
+// bytes written so far. Bytes not accepted by w remain buffered.
+func (b *BufReader) WriteTo(w Writer) (written int64, err error) {
+	for {
+		for b.rd < b.wr {
+			nw, ew := w.Write(b.buf[b.rd:b.wr])
+			if nw > 0 {
+				b.rd += nw
+				written += int64(nw)
+			}
+			if ew != nil {
+				return written, ew
+			}
+			if nw == 0 {
+				return written, io.ErrShortWrite
+			}
+		}
+		if b.err != nil {
+			err = b.takeErr()
+			if err == io.EOF {
+				return written, nil
+			}
+			return written, err
+		}
+		if !b.fill() {
+			return written, nil
+		}
+	}
+}
+
+// MinReader returns a Reader that coalesces small reads from r, for parsers
+// that want at least minBytes per call. Each Read keeps reading from r into
+// an internal buffer of minBytes until it is full, and only then delivers it.
+// If minBytes <= 1, r itself is returned.
+//
+// Semantics of r are mapped as follows:
+//   - ErrWouldBlock before minBytes have accumulated is returned as
+//     (0, ErrWouldBlock); the bytes read so far stay buffered.
+//   - A (0, nil) read is returned as (0, nil), also keeping buffered bytes.
+//   - ErrMore, EOF, and other errors flush what is buffered, even if short of
+//     minBytes; the error is delivered with the last buffered byte.
+//
+// A p shorter than the buffered data receives a prefix; the rest is
+// delivered by the following Reads before r is read again.
+func MinReader(r Reader, minBytes int) Reader {
+	if minBytes <= 1 {
+		return r
+	}
+	return &minReader{r: r, buf: make([]byte, minBytes)}
+}
+
+type minReader struct {
+	r   Reader
+	buf []byte
+	rd  int   // read position in buf
+	wr  int   // write position in buf
+	err error // pending error that ended accumulation
+}
+
+func (m *minReader) Read(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	// Accumulate only while nothing has been delivered from the buffer.
+	for m.rd == 0 && m.wr < len(m.buf) && m.err == nil {
+		nr, er := m.r.Read(m.buf[m.wr:])
+		m.wr += nr
+		if IsWouldBlock(er) {
+			return 0, er
+		}
+		if er != nil {
+			m.err = er
+			break
+		}
+		if nr == 0 {
+			return 0, nil
+		}
+	}
+	n = copy(p, m.buf[m.rd:m.wr])
+	m.rd += n
+	if m.rd == m.wr {
+		m.rd, m.wr = 0, 0
+		err, m.err = m.err, nil
+	}
+	return n, err
+}
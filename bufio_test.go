@@ -0,0 +1,409 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iox_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"code.hybscloud.com/iox"
+)
+
+// -----------------------------------------------------------------------------
+// BufWriter tests
+// -----------------------------------------------------------------------------
+
+// gateWriter accepts up to quota bytes in total, then returns ErrWouldBlock
+// until quota is raised again.
+type gateWriter struct {
+	quota int
+	buf   bytes.Buffer
+}
+
+func (w *gateWriter) Write(p []byte) (int, error) {
+	n := min(w.quota, len(p))
+	w.buf.Write(p[:n])
+	w.quota -= n
+	if n < len(p) {
+		return n, iox.ErrWouldBlock
+	}
+	return n, nil
+}
+
+func TestBufWriter_BufferFullBackpressure(t *testing.T) {
+	dst := &gateWriter{}
+	bw := iox.NewBufWriter(dst, 4)
+	n, err := bw.Write([]byte("abcdef"))
+	if !errors.Is(err, iox.ErrWouldBlock) || n != 4 {
+		t.Fatalf("want (4, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	if bw.Buffered() != 4 || bw.Available() != 0 || bw.Size() != 4 {
+		t.Fatalf("buffered=%d available=%d size=%d", bw.Buffered(), bw.Available(), bw.Size())
+	}
+	if dst.buf.Len() != 0 {
+		t.Fatalf("underlying writer received %q", dst.buf.String())
+	}
+}
+
+func TestBufWriter_PartialFlushAccounting(t *testing.T) {
+	dst := &gateWriter{}
+	bw := iox.NewBufWriter(dst, 8)
+	if n, err := bw.Write([]byte("abcdef")); err != nil || n != 6 {
+		t.Fatalf("n=%d err=%v", n, err)
+	}
+	dst.quota = 4
+	if err := bw.Flush(); !errors.Is(err, iox.ErrWouldBlock) {
+		t.Fatalf("want ErrWouldBlock got %v", err)
+	}
+	if bw.Buffered() != 2 || dst.buf.String() != "abcd" {
+		t.Fatalf("buffered=%d dst=%q", bw.Buffered(), dst.buf.String())
+	}
+	// Freed space is usable before the remainder is flushed.
+	if n, err := bw.Write([]byte("ghijkl")); err != nil || n != 6 {
+		t.Fatalf("n=%d err=%v", n, err)
+	}
+	dst.quota = 100
+	if err := bw.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if bw.Buffered() != 0 || dst.buf.String() != "abcdefghijkl" {
+		t.Fatalf("buffered=%d dst=%q", bw.Buffered(), dst.buf.String())
+	}
+}
+
+func TestBufWriter_FullDrainOnReadyWriter(t *testing.T) {
+	var dst sliceWriter
+	bw := iox.NewBufWriter(&dst, 4)
+	n, err := bw.Write([]byte("0123456789"))
+	if err != nil || n != 10 {
+		t.Fatalf("n=%d err=%v", n, err)
+	}
+	if err := bw.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if string(dst.data) != "0123456789" || bw.Buffered() != 0 {
+		t.Fatalf("dst=%q buffered=%d", string(dst.data), bw.Buffered())
+	}
+}
+
+func TestBufWriter_FlushZeroNilIsShortWrite(t *testing.T) {
+	bw := iox.NewBufWriter(shortZeroWriter{}, 0)
+	if bw.Size() != len(iox.Buffer{}) {
+		t.Fatalf("default size=%d", bw.Size())
+	}
+	_, _ = bw.Write([]byte("x"))
+	if err := bw.Flush(); !errors.Is(err, iox.ErrShortWrite) {
+		t.Fatalf("want ErrShortWrite got %v", err)
+	}
+}
+
+func TestBufWriter_ReaderFromFastPath(t *testing.T) {
+	dst := &gateWriter{}
+	bw := iox.NewBufWriter(dst, 4)
+	src := bytes.NewReader([]byte("abcdefgh"))
+	// noWTReader hides WriterTo so Copy selects bw.ReadFrom.
+	n, err := iox.Copy(bw, noWTReader{src})
+	if !errors.Is(err, iox.ErrWouldBlock) || n != 4 || bw.Buffered() != 4 {
+		t.Fatalf("want (4, ErrWouldBlock) got (%d, %v) buffered=%d", n, err, bw.Buffered())
+	}
+	dst.quota = 100
+	n, err = iox.Copy(bw, noWTReader{src})
+	if err != nil || n != 4 {
+		t.Fatalf("n=%d err=%v", n, err)
+	}
+	if err := bw.Flush(); err != nil || dst.buf.String() != "abcdefgh" {
+		t.Fatalf("flush err=%v dst=%q", err, dst.buf.String())
+	}
+}
+
+func TestBufWriter_ReadFromPropagatesReadSemantics(t *testing.T) {
+	bw := iox.NewBufWriter(&sliceWriter{}, 8)
+	n, err := bw.ReadFrom(&dataThenErrReader{data: []byte("ab"), err: iox.ErrMore})
+	if !errors.Is(err, iox.ErrMore) || n != 2 || bw.Buffered() != 2 {
+		t.Fatalf("want (2, ErrMore) got (%d, %v) buffered=%d", n, err, bw.Buffered())
+	}
+	n, err = bw.ReadFrom(&zeroThenNilReader{})
+	if err != nil || n != 0 {
+		t.Fatalf("want (0, nil) got (%d, %v)", n, err)
+	}
+}
+
+func TestFlush_PreservesWouldBlockAndProgress(t *testing.T) {
+	conn := &gateWriter{quota: 3}
+	fw := iox.NewFlushWriter(conn, 8)
+	if n, err := fw.Write([]byte("abcdef")); err != nil || n != 6 {
+		t.Fatalf("n=%d err=%v", n, err)
+	}
+	if err := iox.Flush(fw); !errors.Is(err, iox.ErrWouldBlock) {
+		t.Fatalf("want ErrWouldBlock got %v", err)
+	}
+	if conn.buf.String() != "abc" {
+		t.Fatalf("conn=%q", conn.buf.String())
+	}
+	conn.quota = 100
+	if err := iox.Flush(fw); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if conn.buf.String() != "abcdef" {
+		t.Fatalf("conn=%q", conn.buf.String())
+	}
+}
+
+func TestAtomicFrameWriter_PartialThenRetry(t *testing.T) {
+	conn := &gateWriter{quota: 3}
+	fw := iox.AtomicFrameWriter(conn)
+	if n, err := fw.Write([]byte("frame1")); n != 0 || !errors.Is(err, iox.ErrWouldBlock) {
+		t.Fatalf("want (0, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	// The remainder cannot be sent yet: the retry is refused again.
+	if n, err := fw.Write([]byte("frame1")); n != 0 || !errors.Is(err, iox.ErrWouldBlock) {
+		t.Fatalf("blocked retry: want (0, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	conn.quota = 100
+	// The retry completes the buffered frame without resending it.
+	if n, err := fw.Write([]byte("frame1")); n != 6 || err != nil {
+		t.Fatalf("retry: n=%d err=%v", n, err)
+	}
+	if n, err := fw.Write([]byte("frame2")); n != 6 || err != nil {
+		t.Fatalf("next frame: n=%d err=%v", n, err)
+	}
+	if conn.buf.String() != "frame1frame2" {
+		t.Fatalf("conn=%q", conn.buf.String())
+	}
+}
+
+func TestAtomicFrameWriter_FlushCompletesFrame(t *testing.T) {
+	conn := &gateWriter{quota: 2}
+	fw := iox.AtomicFrameWriter(conn)
+	if n, err := fw.Write([]byte("hello")); n != 0 || !errors.Is(err, iox.ErrWouldBlock) {
+		t.Fatalf("want (0, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	conn.quota = 2
+	if err := fw.Flush(); !errors.Is(err, iox.ErrWouldBlock) {
+		t.Fatalf("want ErrWouldBlock got %v", err)
+	}
+	conn.quota = 1
+	if err := fw.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if conn.buf.String() != "hello" {
+		t.Fatalf("conn=%q", conn.buf.String())
+	}
+	// Nothing accepted: no buffering, the caller retries the same frame.
+	if n, err := fw.Write([]byte("x")); n != 0 || !errors.Is(err, iox.ErrWouldBlock) {
+		t.Fatalf("want (0, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	if err := fw.Flush(); err != nil || conn.buf.String() != "hello" {
+		t.Fatalf("flush err=%v conn=%q", err, conn.buf.String())
+	}
+}
+
+func TestAtomicFrameWriter_FlushThenNewFrame(t *testing.T) {
+	conn := &gateWriter{quota: 2}
+	fw := iox.AtomicFrameWriter(conn)
+	if n, err := fw.Write([]byte("AAAA")); n != 0 || !errors.Is(err, iox.ErrWouldBlock) {
+		t.Fatalf("want (0, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	conn.quota = 100
+	if err := fw.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	// The frame is complete, so a different frame is written, not absorbed.
+	if n, err := fw.Write([]byte("BBBB")); n != 4 || err != nil {
+		t.Fatalf("new frame=(%d,%v)", n, err)
+	}
+	if conn.buf.String() != "AAAABBBB" {
+		t.Fatalf("conn=%q", conn.buf.String())
+	}
+
+	// A different frame while one is pending completes the pending one first.
+	conn = &gateWriter{quota: 2}
+	fw = iox.AtomicFrameWriter(conn)
+	if n, err := fw.Write([]byte("CCCC")); n != 0 || !errors.Is(err, iox.ErrWouldBlock) {
+		t.Fatalf("want (0, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	conn.quota = 100
+	if n, err := fw.Write([]byte("DD")); n != 2 || err != nil || conn.buf.String() != "CCCCDD" {
+		t.Fatalf("next frame=(%d,%v) conn=%q", n, err, conn.buf.String())
+	}
+}
+
+func TestAtomicFrameWriter_CopyRetriesWithoutDuplicating(t *testing.T) {
+	dst := &wrappedWBWriter{partial: 3}
+	n, err := iox.CopyPolicy(iox.AtomicFrameWriter(dst), &plainReader{data: []byte("frame1")}, iox.YieldPolicy{})
+	if n != 6 || err != nil || string(dst.buf) != "frame1" {
+		t.Fatalf("CopyPolicy=(%d,%v) dst=%q", n, err, dst.buf)
+	}
+
+	// Plain Copy from a seekable source: the resumed Copy re-reads the frame
+	// and its retry completes it.
+	dst = &wrappedWBWriter{partial: 3}
+	fw := iox.AtomicFrameWriter(dst)
+	src := bytes.NewReader([]byte("frame1"))
+	if n, err = iox.Copy(fw, src); n != 0 || !iox.IsWouldBlock(err) {
+		t.Fatalf("first=(%d,%v) want (0, ErrWouldBlock)", n, err)
+	}
+	if n, err = iox.Copy(fw, src); n != 6 || err != nil || string(dst.buf) != "frame1" {
+		t.Fatalf("resume=(%d,%v) dst=%q", n, err, dst.buf)
+	}
+}
+
+func TestFlush_NonFlushWriterIsNoop(t *testing.T) {
+	var dst sliceWriter
+	if err := iox.Flush(&dst); err != nil {
+		t.Fatalf("want nil got %v", err)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// BufReader tests
+// -----------------------------------------------------------------------------
+
+// countingReader records how many times Read was called.
+type countingReader struct {
+	r     iox.Reader
+	calls int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	c.calls++
+	return c.r.Read(p)
+}
+
+func TestBufReader_RefillOnlyWhenEmpty(t *testing.T) {
+	src := &countingReader{r: bytes.NewReader([]byte("abcdefgh"))}
+	br := iox.NewBufReader(src, 4)
+	var got []byte
+	var b [3]byte
+	for {
+		n, err := br.Read(b[:])
+		got = append(got, b[:n]...)
+		if err == iox.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+	}
+	if string(got) != "abcdefgh" {
+		t.Fatalf("got %q", string(got))
+	}
+	// Two fills of 4 bytes plus the final EOF read.
+	if src.calls != 3 {
+		t.Fatalf("underlying reads=%d want 3", src.calls)
+	}
+}
+
+func TestBufReader_WouldBlockOnEmptyBuffer(t *testing.T) {
+	br := iox.NewBufReader(&wbThenDataReader{data: []byte("ok")}, 8)
+	var b [8]byte
+	n, err := br.Read(b[:])
+	if !errors.Is(err, iox.ErrWouldBlock) || n != 0 {
+		t.Fatalf("want (0, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	n, err = br.Read(b[:])
+	if n != 2 || string(b[:n]) != "ok" {
+		t.Fatalf("n=%d err=%v data=%q", n, err, string(b[:n]))
+	}
+}
+
+func TestBufReader_ZeroNilIsNotEOF(t *testing.T) {
+	br := iox.NewBufReader(&zeroThenNilReader{}, 8)
+	var b [8]byte
+	if n, err := br.Read(b[:]); n != 0 || err != nil {
+		t.Fatalf("want (0, nil) got (%d, %v)", n, err)
+	}
+	if n, err := br.Read(b[:]); n != 0 || err != iox.EOF {
+		t.Fatalf("want (0, EOF) got (%d, %v)", n, err)
+	}
+}
+
+func TestBufReader_ErrMoreAfterBufferedBytes(t *testing.T) {
+	br := iox.NewBufReader(&dataThenErrReader{data: []byte("abcd"), err: iox.ErrMore}, 8)
+	var b [3]byte
+	n, err := br.Read(b[:])
+	if err != nil || n != 3 {
+		t.Fatalf("want (3, nil) got (%d, %v)", n, err)
+	}
+	if br.Buffered() != 1 {
+		t.Fatalf("buffered=%d", br.Buffered())
+	}
+	n, err = br.Read(b[:])
+	if !errors.Is(err, iox.ErrMore) || n != 1 || b[0] != 'd' {
+		t.Fatalf("want (1, ErrMore) got (%d, %v)", n, err)
+	}
+}
+
+func TestBufReader_WriterToDrains(t *testing.T) {
+	br := iox.NewBufReader(&moreChunksReader{chunks: [][]byte{[]byte("ab"), []byte("cd")}}, 4)
+	var dst sliceWriter
+	n, err := iox.Copy(&dst, br)
+	if !errors.Is(err, iox.ErrMore) || n != 2 {
+		t.Fatalf("want (2, ErrMore) got (%d, %v)", n, err)
+	}
+	n, err = iox.Copy(&dst, br)
+	if err != nil || n != 2 || string(dst.data) != "abcd" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, string(dst.data))
+	}
+}
+
+func TestBufReader_WriterToKeepsUnwrittenBytes(t *testing.T) {
+	br := iox.NewBufReader(&plainReader{data: []byte("hello")}, 8)
+	dst := &gateWriter{quota: 2}
+	n, err := br.WriteTo(dst)
+	if !errors.Is(err, iox.ErrWouldBlock) || n != 2 || br.Buffered() != 3 {
+		t.Fatalf("want (2, ErrWouldBlock) got (%d, %v) buffered=%d", n, err, br.Buffered())
+	}
+	dst.quota = 100
+	n, err = br.WriteTo(dst)
+	if err != nil || n != 3 || dst.buf.String() != "hello" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, dst.buf.String())
+	}
+}
+
+// -----------------------------------------------------------------------------
+// MinReader tests
+// -----------------------------------------------------------------------------
+
+func TestMinReader_CoalescesSmallReads(t *testing.T) {
+	src := &scriptedReader{steps: []scriptStep{{b: []byte("ab")}, {b: []byte("cd")}, {b: []byte("ef")}}}
+	mr := iox.MinReader(src, 4)
+	var b [8]byte
+	if n, err := mr.Read(b[:]); n != 4 || err != nil || string(b[:n]) != "abcd" {
+		t.Fatalf("first=(%d,%v,%q) want (4,nil,\"abcd\")", n, err, b[:n])
+	}
+	// EOF flushes the short tail.
+	if n, err := mr.Read(b[:]); n != 2 || err != iox.EOF || string(b[:n]) != "ef" {
+		t.Fatalf("tail=(%d,%v,%q) want (2,EOF,\"ef\")", n, err, b[:n])
+	}
+}
+
+func TestMinReader_WouldBlockBelowMin(t *testing.T) {
+	src := &scriptedReader{steps: []scriptStep{{b: []byte("ab")}, {err: iox.ErrWouldBlock}, {b: []byte("cd")}}}
+	mr := iox.MinReader(src, 4)
+	var b [8]byte
+	if n, err := mr.Read(b[:]); n != 0 || err != iox.ErrWouldBlock {
+		t.Fatalf("want (0, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	if n, err := mr.Read(b[:]); n != 4 || err != nil || string(b[:n]) != "abcd" {
+		t.Fatalf("after ready=(%d,%v,%q)", n, err, b[:n])
+	}
+}
+
+func TestMinReader_ErrMoreFlushes(t *testing.T) {
+	src := &scriptedReader{steps: []scriptStep{{b: []byte("abc"), err: iox.ErrMore}, {b: []byte("defgh")}}}
+	mr := iox.MinReader(src, 4)
+	var b [2]byte
+	if n, err := mr.Read(b[:]); n != 2 || err != nil || string(b[:n]) != "ab" {
+		t.Fatalf("first=(%d,%v,%q)", n, err, b[:n])
+	}
+	if n, err := mr.Read(b[:]); n != 1 || err != iox.ErrMore || string(b[:n]) != "c" {
+		t.Fatalf("second=(%d,%v,%q) want (1,ErrMore,\"c\")", n, err, b[:n])
+	}
+	if iox.MinReader(src, 1) != iox.Reader(src) {
+		t.Fatal("minBytes <= 1 should return r")
+	}
+}
@@ -0,0 +1,127 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iox
+
+import "time"
+
+// ChanReader returns a Reader that receives messages from ch without
+// blocking, bridging goroutine pipelines into the Copy family.
+//
+// Each Read yields the bytes of one message: if p is shorter than the
+// message, the rest is delivered by the following Reads before the next
+// receive. When no message is ready, Read returns (0, ErrWouldBlock); once ch
+// is closed and drained, it returns (0, EOF). An empty message reads as
+// (0, nil). Copying from a ChanReader thus moves one message per read step and
+// stops with ErrWouldBlock whenever the channel is momentarily empty.
+//
+// The returned Reader is not safe for concurrent use.
+func ChanReader(ch <-chan []byte) Reader { return &chanReader{ch: ch} }
+
+type chanReader struct {
+	ch   <-chan []byte
+	rest []byte // undelivered tail of the current message
+}
+
+func (c *chanReader) Read(p []byte) (int, error) {
+	if len(c.rest) > 0 {
+		n := copy(p, c.rest)
+		c.rest = c.rest[n:]
+		return n, nil
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	select {
+	case b, ok := <-c.ch:
+		if !ok {
+			return 0, EOF
+		}
+		n := copy(p, b)
+		c.rest = b[n:]
+		return n, nil
+	default:
+		return 0, ErrWouldBlock
+	}
+}
+
+// ChanWriter returns a Writer that sends each Write as one message on ch
+// without blocking. The message is a copy of p, so callers may reuse p.
+//
+// If the send would block (ch is full or has no ready receiver), Write
+// returns (0, ErrWouldBlock) and nothing is sent. A zero-length p sends
+// nothing and returns (0, nil). As with any send, writing after ch is closed
+// panics.
+func ChanWriter(ch chan<- []byte) Writer { return chanWriter{ch: ch} }
+
+type chanWriter struct{ ch chan<- []byte }
+
+func (c chanWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	b := append([]byte(nil), p...)
+	select {
+	case c.ch <- b:
+		return len(p), nil
+	default:
+		return 0, ErrWouldBlock
+	}
+}
+
+// NewReadyReader returns a Reader that bridges a non-blocking r with a
+// readiness channel, such as one fed by a netpoller: when r.Read returns
+// (0, ErrWouldBlock), Read checks ready once and, if a signal is pending,
+// retries r.Read exactly once and returns its result. Without a pending
+// signal it returns (0, ErrWouldBlock) immediately.
+//
+// Each retry consumes one signal; a closed ready lets every would-block Read
+// retry once. Data and other results of r pass through unchanged.
+func NewReadyReader(r Reader, ready <-chan struct{}) Reader {
+	return NewReadyReaderTimeout(r, ready, 0)
+}
+
+// NewReadyReaderTimeout is like NewReadyReader but, when no signal is
+// pending, waits up to timeout for one before giving up with
+// (0, ErrWouldBlock). A timeout <= 0 does not wait.
+func NewReadyReaderTimeout(r Reader, ready <-chan struct{}, timeout time.Duration) Reader {
+	return &readyReader{r: r, ready: ready, timeout: timeout}
+}
+
+type readyReader struct {
+	r       Reader
+	ready   <-chan struct{}
+	timeout time.Duration
+}
+
+func (c *readyReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if n > 0 || !IsWouldBlock(err) {
+		return n, err
+	}
+	if !c.wait() {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+// wait reports whether a readiness signal arrived.
+func (c *readyReader) wait() bool {
+	select {
+	case <-c.ready:
+		return true
+	default:
+	}
+	if c.timeout <= 0 {
+		return false
+	}
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+	select {
+	case <-c.ready:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
@@ -0,0 +1,115 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iox_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"code.hybscloud.com/iox"
+)
+
+func TestChanReader(t *testing.T) {
+	ch := make(chan []byte, 4)
+	r := iox.ChanReader(ch)
+	var p [4]byte
+	if n, err := r.Read(p[:]); n != 0 || err != iox.ErrWouldBlock {
+		t.Fatalf("empty=(%d,%v) want (0,ErrWouldBlock)", n, err)
+	}
+
+	ch <- []byte("hello")
+	ch <- []byte("ok")
+	if n, err := r.Read(p[:]); n != 4 || err != nil || string(p[:n]) != "hell" {
+		t.Fatalf("first=(%d,%v,%q)", n, err, p[:n])
+	}
+	// The message tail is delivered before the next message.
+	if n, err := r.Read(p[:]); n != 1 || err != nil || string(p[:n]) != "o" {
+		t.Fatalf("tail=(%d,%v,%q)", n, err, p[:n])
+	}
+	if n, err := r.Read(p[:]); n != 2 || err != nil || string(p[:n]) != "ok" {
+		t.Fatalf("second=(%d,%v,%q)", n, err, p[:n])
+	}
+
+	close(ch)
+	if n, err := r.Read(p[:]); n != 0 || err != iox.EOF {
+		t.Fatalf("closed=(%d,%v) want (0,EOF)", n, err)
+	}
+}
+
+func TestChanReader_Copy(t *testing.T) {
+	ch := make(chan []byte, 2)
+	ch <- []byte("ab")
+	ch <- []byte("cd")
+	var dst bytes.Buffer
+	src := iox.ChanReader(ch)
+	if n, err := iox.Copy(&dst, src); n != 4 || err != iox.ErrWouldBlock {
+		t.Fatalf("copy=(%d,%v) want (4,ErrWouldBlock)", n, err)
+	}
+	ch <- []byte("ef")
+	close(ch)
+	if n, err := iox.Copy(&dst, src); n != 2 || err != nil || dst.String() != "abcdef" {
+		t.Fatalf("drain=(%d,%v,%q)", n, err, dst.String())
+	}
+}
+
+func TestChanWriter(t *testing.T) {
+	ch := make(chan []byte, 1)
+	w := iox.ChanWriter(ch)
+	p := []byte("msg")
+	if n, err := w.Write(p); n != 3 || err != nil {
+		t.Fatalf("first=(%d,%v)", n, err)
+	}
+	p[0] = 'X' // the sent message is a copy
+	if n, err := w.Write([]byte("full")); n != 0 || err != iox.ErrWouldBlock {
+		t.Fatalf("full=(%d,%v) want (0,ErrWouldBlock)", n, err)
+	}
+	if got := string(<-ch); got != "msg" {
+		t.Fatalf("received %q", got)
+	}
+	if n, err := w.Write(nil); n != 0 || err != nil || len(ch) != 0 {
+		t.Fatalf("empty=(%d,%v) len=%d", n, err, len(ch))
+	}
+}
+
+func TestReadyReader(t *testing.T) {
+	src := &wbTwiceReader{data: []byte("ready")}
+	ready := make(chan struct{}, 2)
+	rr := iox.NewReadyReader(src, ready)
+	var p [8]byte
+	if n, err := rr.Read(p[:]); n != 0 || err != iox.ErrWouldBlock {
+		t.Fatalf("no signal=(%d,%v) want (0,ErrWouldBlock)", n, err)
+	}
+	ready <- struct{}{}
+	if n, err := rr.Read(p[:]); n != 5 || err != nil || string(p[:n]) != "ready" {
+		t.Fatalf("signalled=(%d,%v,%q)", n, err, p[:n])
+	}
+	if len(ready) != 0 {
+		t.Fatal("signal not consumed")
+	}
+
+	// A signal buys exactly one retry.
+	ready <- struct{}{}
+	rr = iox.NewReadyReader(&wbTwiceReader{data: []byte("x")}, ready)
+	if n, err := rr.Read(p[:]); n != 0 || err != iox.ErrWouldBlock {
+		t.Fatalf("one retry=(%d,%v) want (0,ErrWouldBlock)", n, err)
+	}
+}
+
+func TestReadyReaderTimeout(t *testing.T) {
+	ready := make(chan struct{})
+	src := &scriptedReader{steps: []scriptStep{{err: iox.ErrWouldBlock}, {b: []byte("x")}}}
+	rr := iox.NewReadyReaderTimeout(src, ready, time.Second)
+	go func() { ready <- struct{}{} }()
+	var p [4]byte
+	if n, err := rr.Read(p[:]); n != 1 || err != nil {
+		t.Fatalf("signalled=(%d,%v)", n, err)
+	}
+
+	rr = iox.NewReadyReaderTimeout(errReaderAlwaysWB{}, ready, 5*time.Millisecond)
+	if n, err := rr.Read(p[:]); n != 0 || err != iox.ErrWouldBlock {
+		t.Fatalf("timeout=(%d,%v) want (0,ErrWouldBlock)", n, err)
+	}
+}
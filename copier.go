@@ -0,0 +1,75 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iox
+
+// Copier copies repeatedly between a fixed dst and src, e.g., a long-lived
+// proxy connection that calls Copy in a loop. NewCopier selects the copy path
+// once, so each Copy skips the WriterTo / ReaderFrom interface assertions that
+// the package-level Copy performs on every call.
+//
+// Copy and CopyPolicy have the same semantics as Copy(dst, src) and
+// CopyPolicy(dst, src, policy), including EOF mapped to nil, unchanged
+// ErrWouldBlock / ErrMore, and Seeker rollback on the read/write loop.
+// A Copier is not safe for concurrent use.
+type Copier struct {
+	dst Writer
+	src Reader
+	wt  WriterTo   // src, if it implements WriterTo
+	rf  ReaderFrom // dst, if it implements ReaderFrom and src is not a WriterTo
+}
+
+// NewCopier returns a Copier from src to dst. The path is chosen as in Copy:
+// src's WriterTo first, then dst's ReaderFrom, then the read/write loop.
+func NewCopier(dst Writer, src Reader) *Copier {
+	c := &Copier{dst: dst, src: src}
+	if wt, ok := src.(WriterTo); ok {
+		c.wt = wt
+	} else if rf, ok := dst.(ReaderFrom); ok {
+		c.rf = rf
+	}
+	return c
+}
+
+// slowPathOpts runs copyBuffer's read/write loop without re-checking the
+// fast paths a Copier has already ruled out.
+var slowPathOpts = CopyOpts{DisableFastPath: true}
+
+// Copy copies from src to dst until EOF or an error, as Copy does.
+func (c *Copier) Copy() (written int64, err error) {
+	if c.dst == nil || c.src == nil {
+		return 0, ErrNilArgument
+	}
+	switch {
+	case c.wt != nil:
+		written, err = c.wt.WriteTo(c.dst)
+	case c.rf != nil:
+		written, err = c.rf.ReadFrom(c.src)
+	default:
+		return copyBuffer(c.dst, c.src, nil, &slowPathOpts)
+	}
+	if err == EOF {
+		err = nil
+	}
+	return written, err
+}
+
+// CopyPolicy copies from src to dst as CopyPolicy does, consulting policy on
+// semantic errors. A nil policy is identical to c.Copy().
+func (c *Copier) CopyPolicy(policy SemanticPolicy) (written int64, err error) {
+	if policy == nil {
+		return c.Copy()
+	}
+	if c.dst == nil || c.src == nil {
+		return 0, ErrNilArgument
+	}
+	switch {
+	case c.wt != nil:
+		return writeToPolicy(c.dst, c.wt, policy, nil)
+	case c.rf != nil:
+		return readFromPolicy(c.rf, c.src, policy, nil)
+	default:
+		return copyBufferPolicy(c.dst, c.src, nil, policy, &slowPathOpts)
+	}
+}
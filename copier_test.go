@@ -0,0 +1,90 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iox_test
+
+import (
+	"bytes"
+	"testing"
+
+	"code.hybscloud.com/iox"
+)
+
+func TestCopier_Reuse(t *testing.T) {
+	src := &moreChunksReader{chunks: [][]byte{[]byte("ab"), []byte("cd")}}
+	var dst sliceWriter
+	c := iox.NewCopier(&dst, src)
+	if n, err := c.Copy(); n != 2 || err != iox.ErrMore {
+		t.Fatalf("first=(%d,%v) want (2,ErrMore)", n, err)
+	}
+	if n, err := c.Copy(); n != 2 || err != nil || string(dst.data) != "abcd" {
+		t.Fatalf("second=(%d,%v,%q)", n, err, dst.data)
+	}
+
+	// The WriterTo path maps EOF to nil.
+	var buf bytes.Buffer
+	c = iox.NewCopier(&buf, eofWT{data: []byte("wt")})
+	if n, err := c.Copy(); n != 2 || err != nil || buf.String() != "wt" {
+		t.Fatalf("WriterTo=(%d,%v,%q)", n, err, buf.String())
+	}
+
+	if _, err := iox.NewCopier(nil, src).Copy(); err != iox.ErrNilArgument {
+		t.Fatalf("nil dst err=%v", err)
+	}
+}
+
+// eofWT is a WriterTo that reports io.EOF after writing its data.
+type eofWT struct{ data []byte }
+
+func (w eofWT) Read(p []byte) (int, error) { return 0, iox.EOF }
+
+func (w eofWT) WriteTo(dst iox.Writer) (int64, error) {
+	n, err := dst.Write(w.data)
+	if err != nil {
+		return int64(n), err
+	}
+	return int64(n), iox.EOF
+}
+
+func TestCopier_MatchesCopy(t *testing.T) {
+	data := []byte("copier-data")
+	paths := []struct {
+		name string
+		mk   func() (iox.Writer, iox.Reader, func() string)
+	}{
+		{"WriterTo", func() (iox.Writer, iox.Reader, func() string) {
+			var dst sliceWriter
+			return &dst, eofWT{data: data}, func() string { return string(dst.data) }
+		}},
+		{"ReaderFrom", func() (iox.Writer, iox.Reader, func() string) {
+			var dst bytes.Buffer
+			return &dst, &plainReader{data: data}, dst.String
+		}},
+		{"ReadWrite", func() (iox.Writer, iox.Reader, func() string) {
+			var dst sliceWriter
+			return &dst, &wbTwiceReader{data: data}, func() string { return string(dst.data) }
+		}},
+	}
+	for _, tc := range paths {
+		for _, policy := range []iox.SemanticPolicy{nil, iox.YieldPolicy{}} {
+			dst, src, got := tc.mk()
+			wantN, wantErr := iox.CopyPolicy(dst, src, policy)
+			want := got()
+
+			dst, src, got = tc.mk()
+			c := iox.NewCopier(dst, src)
+			var n int64
+			var err error
+			if policy == nil {
+				n, err = c.Copy()
+			} else {
+				n, err = c.CopyPolicy(policy)
+			}
+			if n != wantN || err != wantErr || got() != want {
+				t.Fatalf("%s policy=%v: Copier=(%d,%v,%q) package=(%d,%v,%q)",
+					tc.name, policy, n, err, got(), wantN, wantErr, want)
+			}
+		}
+	}
+}
@@ -4,7 +4,10 @@
 
 package iox
 
-import "errors"
+import (
+	"errors"
+	"strconv"
+)
 
 // iox introduces two semantic errors for non-blocking and multi-shot I/O.
 //
@@ -27,6 +30,12 @@ var ErrWouldBlock = errors.New("io: would block")
 // Next step: keep polling and processing results.
 var ErrMore = errors.New("io: expect more")
 
+// ErrTimeout means “gave up waiting for progress”.
+// Unlike ErrWouldBlock, the caller already waited (for a deadline or a retry
+// budget) and the wait expired before the operation could proceed.
+// Next step: decide whether to wait again, back off, or tear down.
+var ErrTimeout = errors.New("io: timeout")
+
 // ErrNoSeeker is returned by Copy helpers when a partial write occurs with a
 // semantic error (ErrWouldBlock or ErrMore) and the source does not implement
 // io.Seeker. Without Seek capability, the unwritten bytes in the transient
@@ -36,4 +45,66 @@ var ErrMore = errors.New("io: expect more")
 //   - Use a seekable source (e.g., *os.File, *bytes.Reader).
 //   - Use CopyPolicy with PolicyRetry to ensure all read bytes are written
 //     before returning.
+//
+// Helpers return the sentinel itself; callers that wrap it (e.g., with
+// fmt.Errorf("...: %w", err)) can still detect it with errors.Is or IsNoSeeker.
 var ErrNoSeeker = errors.New("io: source is not seekable; partial write unrecoverable")
+
+// ErrNilArgument is returned by the Copy family, and by the Read / Write of
+// Tee adapters, when a required Reader or Writer argument is nil. It is
+// reported before any I/O, instead of a nil-dereference panic deep inside
+// the engine.
+var ErrNilArgument = errors.New("io: nil reader or writer")
+
+// ErrOverRead is returned by CopyN and CopyNBuffer when dst's ReadFrom reports
+// a byte count that differs from what it actually consumed from src, e.g.,
+// more than the n it was allowed to read. The count returned with it is the
+// bytes actually consumed from src; dst's own state is suspect.
+var ErrOverRead = errors.New("io: ReaderFrom reported more bytes than available")
+
+// ErrClosed means “the endpoint was closed mid-transfer”.
+// Non-blocking endpoints return it so a closure can be told apart from
+// generic failures (see IsClosed and OutcomeClosed). Unlike ErrWouldBlock it
+// is a failure: no retry will make progress.
+var ErrClosed = errors.New("io: endpoint closed")
+
+// ErrChecksumMismatch is returned by CopyVerify when the copy completed but
+// the checksum of the copied bytes differs from the expected one.
+var ErrChecksumMismatch = errors.New("io: checksum mismatch")
+
+// WrapWouldBlock returns an error that carries the ErrWouldBlock semantic and
+// whose message is annotated with msg (e.g., which descriptor stalled).
+//
+// The result matches ErrWouldBlock via errors.Is, so IsWouldBlock, Classify,
+// and the Copy/Tee helpers treat it exactly like the bare sentinel. Helpers
+// that return a semantic error pass the annotated value through unchanged.
+func WrapWouldBlock(msg string) error { return &semanticError{msg: msg, err: ErrWouldBlock} }
+
+// WrapMore returns an error that carries the ErrMore semantic and whose
+// message is annotated with msg. See WrapWouldBlock.
+func WrapMore(msg string) error { return &semanticError{msg: msg, err: ErrMore} }
+
+// semanticError annotates a semantic sentinel with caller context.
+type semanticError struct {
+	msg string
+	err error
+}
+
+func (e *semanticError) Error() string { return e.msg + ": " + e.err.Error() }
+
+func (e *semanticError) Unwrap() error { return e.err }
+
+// SourceError reports which source of a multi-source helper (e.g.,
+// CopyAllFrom) stopped the operation. Err is that source's error, which may
+// be ErrWouldBlock or ErrMore; it is reachable via errors.Is / errors.As, so
+// IsWouldBlock and Classify see through the wrapper.
+type SourceError struct {
+	Index int   // index of the source in the argument list
+	Err   error // error returned while copying from that source
+}
+
+func (e *SourceError) Error() string {
+	return "source " + strconv.Itoa(e.Index) + ": " + e.Err.Error()
+}
+
+func (e *SourceError) Unwrap() error { return e.Err }
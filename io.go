@@ -5,7 +5,12 @@
 package iox
 
 import (
+	"bytes"
+	"encoding/binary"
+	"hash"
 	"io"
+	"sync"
+	"sync/atomic"
 )
 
 // Copy copies from src to dst until either EOF is reached on src or an error occurs.
@@ -28,8 +33,163 @@ import (
 // using non-blocking destinations with non-seekable sources (e.g., sockets) should
 // use CopyPolicy with PolicyRetry to ensure all read bytes are written before
 // returning.
+//
+// A read that completes with (0, nil) ends the copy with (written, nil).
 func Copy(dst Writer, src Reader) (written int64, err error) {
-	return copyBuffer(dst, src, nil)
+	return copyBuffer(dst, src, nil, nil)
+}
+
+// CopyResult is the outcome of a copy delivered as a value, e.g., by CopyE
+// or CopyAsync. Err carries ErrWouldBlock / ErrMore unchanged.
+type CopyResult struct {
+	Written int64
+	Err     error
+
+	// LastOp is the engine step that ended the copy: OpCopyRead or
+	// OpCopyWrite on the read/write loop, OpCopyWriterTo or OpCopyReaderFrom
+	// on a fast path. It is meaningless if no step ran (e.g., ErrNilArgument).
+	LastOp Op
+
+	// Outcome is Classify(Err).
+	Outcome Outcome
+}
+
+// CopyE is like Copy but returns a structured CopyResult alongside the error,
+// telling the caller which side stopped the copy (e.g., OpCopyRead for a
+// would-blocking source, OpCopyWrite for a would-blocking sink).
+func CopyE(dst Writer, src Reader) (CopyResult, error) {
+	var last lastOpObserver
+	n, err := copyBuffer(dst, src, nil, &CopyOpts{Observer: &last})
+	return CopyResult{Written: n, Err: err, LastOp: last.op, Outcome: Classify(err)}, err
+}
+
+// CopyWhere is like Copy but also returns the Op of the step that produced a
+// non-nil error, e.g., OpCopyRead when src would block and OpCopyWrite when
+// dst would block, so the caller can register interest in the right
+// descriptor. On a fast path the Op is OpCopyWriterTo or OpCopyReaderFrom. A
+// clean completion (nil error) reports OpNone.
+func CopyWhere(dst Writer, src Reader) (written int64, op Op, err error) {
+	var last lastOpObserver
+	written, err = copyBuffer(dst, src, nil, &CopyOpts{Observer: &last})
+	if err == nil {
+		return written, OpNone, nil
+	}
+	return written, last.op, err
+}
+
+// CopySplit is like Copy but reports read, the bytes pulled from src, apart
+// from written, the bytes accepted by dst. They differ when a partial
+// semantic write is rolled back with src.Seek: the rolled-back bytes count in
+// read but not in written, and will be read again by the next copy.
+//
+// Fast paths move data in a single delegated step, so read equals written
+// there.
+func CopySplit(dst Writer, src Reader) (read, written int64, err error) {
+	var obs splitObserver
+	written, err = copyBuffer(dst, src, nil, &CopyOpts{Observer: &obs})
+	return obs.read, written, err
+}
+
+// splitObserver sums the bytes each engine step pulled from the source.
+type splitObserver struct{ read int64 }
+
+func (o *splitObserver) ObserveOp(op Op, n int, _ error) {
+	switch op {
+	case OpCopyRead, OpCopyWriterTo, OpCopyReaderFrom:
+		o.read += int64(n)
+	}
+}
+
+// lastOpObserver remembers the Op of the most recent engine step.
+type lastOpObserver struct{ op Op }
+
+func (o *lastOpObserver) ObserveOp(op Op, _ int, _ error) { o.op = op }
+
+// CopyAsync runs CopyE(dst, src) on a new goroutine. The returned channel
+// receives exactly one CopyResult and is then closed; it is buffered, so the
+// goroutine never blocks if the result is not read.
+func CopyAsync(dst Writer, src Reader) <-chan CopyResult {
+	ch := make(chan CopyResult, 1)
+	go func() {
+		defer close(ch)
+		res, _ := CopyE(dst, src)
+		ch <- res
+	}()
+	return ch
+}
+
+// CopyOpts configures CopyWith, CopyWithPolicy, and CopyBufferWith. The zero
+// value selects the same behavior as Copy (or CopyPolicy, CopyBuffer).
+type CopyOpts struct {
+	// DisableFastPath forces the generic read/write loop: the WriterTo and
+	// ReaderFrom fast paths are skipped even when src or dst implement them.
+	// Useful when debugging semantics, or to work around a third-party
+	// WriteTo/ReadFrom that does not preserve them.
+	DisableFastPath bool
+
+	// PreferReaderFrom selects dst's ReaderFrom over src's WriterTo when both
+	// are implemented (e.g., a socket whose ReadFrom uses splice). By default
+	// WriterTo wins, as in io.Copy.
+	PreferReaderFrom bool
+
+	// Observer, if non-nil, is notified after every read/write step of the
+	// copy, including steps that return ErrWouldBlock or ErrMore.
+	Observer CopyObserver
+
+	// DetectConcurrentBuffer makes CopyBufferWith mark buf as in use for the
+	// duration of the copy and panic if another CopyBufferWith with this
+	// option starts on the same buffer (same first element) meanwhile. Two
+	// copies sharing a staging buffer otherwise corrupt data silently. It is
+	// a debugging aid with a small per-call cost; copies without the option
+	// are not checked.
+	DetectConcurrentBuffer bool
+}
+
+// CopyObserver receives per-step events from the copy engine, e.g., to
+// attach tracing spans around semantic events.
+//
+// ObserveOp is called once per completed step with the step's Op, the byte
+// count it reported, and its raw error (before the engine maps io.EOF to
+// nil). Fast paths report a single OpCopyWriterTo / OpCopyReaderFrom step
+// per attempt; the policy engine reports each retried attempt.
+type CopyObserver interface {
+	ObserveOp(op Op, n int, err error)
+}
+
+// CopyWith is like Copy but configured by opts.
+//
+// With the zero CopyOpts it is identical to Copy, including the Seeker
+// rollback and ErrNoSeeker semantics of the read/write loop.
+func CopyWith(dst Writer, src Reader, opts CopyOpts) (written int64, err error) {
+	return copyBuffer(dst, src, nil, &opts)
+}
+
+// CopyWithPolicy is like CopyPolicy but configured by opts. The policy engine
+// honors opts.DisableFastPath and opts.PreferReaderFrom; with DisableFastPath
+// every step goes through the policy-aware read/write loop, which matters for
+// WriterTo / ReaderFrom implementations that do not consult the policy.
+// opts.Observer sees every step, including each retried attempt. A nil policy
+// delegates to CopyWith.
+func CopyWithPolicy(dst Writer, src Reader, policy SemanticPolicy, opts CopyOpts) (written int64, err error) {
+	if policy == nil {
+		return copyBuffer(dst, src, nil, &opts)
+	}
+	return copyBufferPolicy(dst, src, nil, policy, &opts)
+}
+
+// FastPathFor reports which fast path Copy(dst, src) would take, using the
+// same type checks as the engine and without doing any I/O: src's WriterTo
+// if implemented, else dst's ReaderFrom. At most one result is true; both
+// false means the read/write loop (and its Seeker rollback) applies.
+//
+// Wrappers hide fast paths: a src wrapped by, e.g., TeeReader or ChunkReader
+// reports false even if the underlying Reader implements WriterTo.
+func FastPathFor(dst Writer, src Reader) (usesWriterTo bool, usesReaderFrom bool) {
+	if _, ok := src.(WriterTo); ok {
+		return true, false
+	}
+	_, ok := dst.(ReaderFrom)
+	return false, ok
 }
 
 // CopyPolicy is like Copy but consults policy when encountering semantic errors.
@@ -52,9 +212,336 @@ func Copy(dst Writer, src Reader) (written int64, err error) {
 // errors. This guarantees forward progress without data loss.
 func CopyPolicy(dst Writer, src Reader, policy SemanticPolicy) (written int64, err error) {
 	if policy == nil {
-		return copyBuffer(dst, src, nil)
+		return copyBuffer(dst, src, nil, nil)
+	}
+	return copyBufferPolicy(dst, src, nil, policy, nil)
+}
+
+// CopyPolicyCounts is like CopyPolicy but also returns moreCount, the number
+// of ErrMore boundaries the copy crossed: how many times policy.OnMore
+// returned PolicyRetry and the engine continued. Boundaries that end the copy
+// (PolicyReturn) are not counted. A nil policy never retries, so moreCount
+// is 0.
+func CopyPolicyCounts(dst Writer, src Reader, policy SemanticPolicy) (written int64, moreCount int, err error) {
+	if policy == nil {
+		written, err = copyBuffer(dst, src, nil, nil)
+		return written, 0, err
+	}
+	p := &moreCountingPolicy{forwardingPolicy: forwardingPolicy{policy}}
+	written, err = copyBufferPolicy(dst, src, nil, p, nil)
+	return written, p.n, err
+}
+
+// moreCountingPolicy counts OnMore retries of the embedded policy.
+type moreCountingPolicy struct {
+	forwardingPolicy
+	n int
+}
+
+func (p *moreCountingPolicy) OnMore(op Op) PolicyAction {
+	a := p.SemanticPolicy.OnMore(op)
+	if a == PolicyRetry {
+		p.n++
+	}
+	return a
+}
+
+// CopyUntilEOF copies from src to dst across ErrMore boundaries until EOF or
+// a failure, accumulating the total bytes written.
+//
+// It is a drain helper for multi-shot sources: every ErrMore (from either the
+// read or write side) is treated as "keep going", with policy.Yield(op) called
+// between completions. ErrWouldBlock is still decided by policy: PolicyRetry
+// yields and retries, PolicyReturn returns (written, ErrWouldBlock).
+//
+// A nil policy is treated as ReturnPolicy: ErrWouldBlock is returned and
+// consecutive ErrMore completions are consumed without waiting.
+func CopyUntilEOF(dst Writer, src Reader, policy SemanticPolicy) (written int64, err error) {
+	if policy == nil {
+		policy = ReturnPolicy{}
 	}
-	return copyBufferPolicy(dst, src, nil, policy)
+	return copyBufferPolicy(dst, src, nil, retryMorePolicy{forwardingPolicy{policy}}, nil)
+}
+
+// CopyStd is a drop-in replacement for io.Copy in code written for blocking
+// endpoints: it copies until EOF (mapped to nil) or a failure, and never
+// surfaces ErrWouldBlock or ErrMore. Both are retried transparently, first
+// spinning with runtime.Gosched and then sleeping with a Backoff (see
+// AdaptivePolicy), so the call blocks until the copy completes.
+//
+// Use Copy or CopyPolicy to keep non-blocking control in the caller.
+func CopyStd(dst io.Writer, src io.Reader) (written int64, err error) {
+	return copyBufferPolicy(dst, src, nil, retryMorePolicy{forwardingPolicy{AdaptivePolicy(copyStdSpins)}}, nil)
+}
+
+// copyStdSpins is how many consecutive Gosched yields CopyStd spends on an Op
+// before backing off.
+const copyStdSpins = 16
+
+// retryMorePolicy overrides OnMore to always retry, delegating everything
+// else to the embedded policy.
+type retryMorePolicy struct{ forwardingPolicy }
+
+func (retryMorePolicy) OnMore(Op) PolicyAction { return PolicyRetry }
+
+// Drain reads r to EOF, discarding the bytes, and returns how many were
+// drained. EOF is mapped to a nil error.
+//
+// Semantic errors are handled per policy as in CopyPolicy: PolicyRetry yields
+// and keeps draining, PolicyReturn stops with (drained, ErrWouldBlock /
+// ErrMore). A nil policy behaves like ReturnPolicy. As in Copy, a (0, nil)
+// read stops the drain with (drained, nil).
+func Drain(r Reader, policy SemanticPolicy) (drained int64, err error) {
+	return CopyPolicy(discard{}, r, policy)
+}
+
+// discard is a Writer that accepts and drops every byte. Unlike io.Discard it
+// does not implement ReaderFrom, so draining goes through iox's own loops.
+type discard struct{}
+
+func (discard) Write(p []byte) (int, error) { return len(p), nil }
+
+// CopyAllFrom copies each of srcs to dst in order, each until EOF, and
+// returns the total number of bytes written. EOF from one source moves on to
+// the next; the result is (total, nil) once every source is exhausted.
+//
+// On the first ErrWouldBlock, ErrMore, or failure, CopyAllFrom stops without
+// touching later sources and returns a *SourceError carrying the index of the
+// source that stopped and its error. Semantic errors remain detectable with
+// IsWouldBlock / IsMore; resume with CopyAllFrom(dst, srcs[i:]...).
+func CopyAllFrom(dst Writer, srcs ...Reader) (written int64, err error) {
+	for i, src := range srcs {
+		n, e := Copy(dst, src)
+		written += n
+		if e != nil {
+			return written, &SourceError{Index: i, Err: e}
+		}
+	}
+	return written, nil
+}
+
+// CopyLengthPrefixed writes length as a 4-byte big-endian prefix and then
+// copies exactly length bytes from src to dst, for framing layers that
+// length-delimit each payload.
+//
+// The prefix is all-or-nothing: ErrWouldBlock / ErrMore while writing it are
+// retried internally (yielding with runtime.Gosched), so the body never starts
+// after a partial prefix. A failure while writing the prefix is returned with
+// written == 0.
+//
+// The body is copied as by CopyN: written counts payload bytes only (not the
+// prefix), written == length if and only if err == nil, and a source that
+// ends early yields io.ErrUnexpectedEOF. A semantic stop in the body returns
+// ErrWouldBlock / ErrMore with the payload bytes written so far; finish the
+// frame with CopyN(dst, src, int64(length)-written).
+func CopyLengthPrefixed(dst Writer, src Reader, length uint32) (written int64, err error) {
+	if dst == nil || src == nil {
+		return 0, ErrNilArgument
+	}
+	var prefix [4]byte
+	binary.BigEndian.PutUint32(prefix[:], length)
+	for off := 0; off < len(prefix); {
+		n, e := dst.Write(prefix[off:])
+		off += n
+		if e != nil && !IsSemantic(e) {
+			return 0, e
+		}
+		if e == nil && n == 0 {
+			return 0, io.ErrShortWrite
+		}
+		if off < len(prefix) && e != nil {
+			YieldPolicy{}.Yield(OpCopyWrite)
+		}
+	}
+	return CopyN(dst, src, int64(length))
+}
+
+// CopyVerify is like Copy but hashes the bytes dst accepts with h and, once
+// the copy completes, compares h.Sum(nil) with expected, returning
+// ErrChecksumMismatch (with the full written count) if they differ.
+//
+// Only a completed copy is verified: ErrWouldBlock, ErrMore, and failures are
+// returned as Copy returns them, and h keeps the bytes accepted so far, so the
+// caller can resume with the same h. Bytes rolled back after a partial write
+// are not hashed. h is not reset first.
+func CopyVerify(dst Writer, src Reader, expected []byte, h hash.Hash) (written int64, err error) {
+	if dst == nil || src == nil || h == nil {
+		return 0, ErrNilArgument
+	}
+	written, err = Copy(TeeWriter(dst, h), src)
+	if err != nil {
+		return written, err
+	}
+	if !bytes.Equal(h.Sum(nil), expected) {
+		return written, ErrChecksumMismatch
+	}
+	return written, nil
+}
+
+// CopyDuplexStep performs one step of a full-duplex pump: at most one
+// src.Read and one dst.Write attempt, reporting each side independently so an
+// event loop can register interest in whichever side would block.
+//
+// The step stages through a Buffer from an internal pool, so the pump does
+// not allocate per step. The bytes read (up to len(Buffer)) are written once.
+// When the read returns no data with a nil or semantic error, the write
+// attempt is a zero-length probe, Write(nil), so a writer that reports
+// readiness on empty writes still surfaces ErrWouldBlock; other writers
+// return (0, nil), which reads as "not blocked". Once the read side has
+// ended (EOF or another failure) with no data, dst is not touched and wErr is
+// nil. rErr is the raw read error, including EOF.
+//
+// Bytes that dst does not accept are rolled back with src.Seek, as in Copy,
+// so the next step reads them again (readN still counts them). If src is not
+// a Seeker, wErr is ErrNoSeeker; wrap sockets in a RewindReader to avoid
+// that. A partial write with a nil error is reported as io.ErrShortWrite.
+func CopyDuplexStep(dst Writer, src Reader) (readN, wroteN int, rErr, wErr error) {
+	if dst == nil || src == nil {
+		return 0, 0, ErrNilArgument, ErrNilArgument
+	}
+	b := bufferPool.Get().(*Buffer)
+	defer bufferPool.Put(b)
+	readN, rErr = src.Read(b[:])
+	if readN == 0 {
+		if rErr != nil && !IsSemantic(rErr) {
+			return 0, 0, rErr, nil
+		}
+		wroteN, wErr = dst.Write(nil)
+		return 0, wroteN, rErr, wErr
+	}
+	wroteN, wErr = dst.Write(b[:readN])
+	if wroteN < readN {
+		if wErr == nil {
+			return readN, wroteN, rErr, io.ErrShortWrite
+		}
+		if IsSemantic(wErr) {
+			seeker, ok := src.(io.Seeker)
+			if !ok {
+				return readN, wroteN, rErr, ErrNoSeeker
+			}
+			if _, seekErr := seeker.Seek(int64(wroteN-readN), io.SeekCurrent); seekErr != nil {
+				return readN, wroteN, rErr, seekErr
+			}
+		}
+	}
+	return readN, wroteN, rErr, wErr
+}
+
+// CopyAtomic is like Copy for seekable sources, but a failed copy leaves src
+// where it started: it records src's initial offset and, if the copy ends with
+// a failure (anything but nil, ErrWouldBlock, ErrMore, or ErrTimeout; e.g.,
+// io.ErrShortWrite or a sink error), seeks src back to it so the whole
+// operation can be retried cleanly. written still reports the bytes dst
+// received before the failure.
+//
+// On success src is positioned past every byte read. Semantic stops keep
+// Copy's behavior: src is rolled back only by the unwritten amount, so the
+// copy resumes where it stopped. If restoring the offset fails, the seek error
+// is returned.
+func CopyAtomic(dst Writer, src ReadSeeker) (written int64, err error) {
+	if dst == nil || src == nil {
+		return 0, ErrNilArgument
+	}
+	start, err := src.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	written, err = Copy(dst, src)
+	if IsNonFailure(err) {
+		return written, err
+	}
+	if _, seekErr := src.Seek(start, io.SeekStart); seekErr != nil {
+		return written, seekErr
+	}
+	return written, err
+}
+
+// CopyRestartable is like CopyPolicy for idempotent sources that cannot
+// seek. Where CopyPolicy would return ErrNoSeeker (a partial write with a
+// semantic error on a non-seekable source), CopyRestartable discards the
+// attempt, calls policy.Yield(OpCopyWrite), obtains a fresh Reader from src,
+// and copies again from the beginning. written counts only the final attempt.
+//
+// Requirements:
+//   - src must be idempotent: every call returns a Reader producing the same
+//     bytes from the start (e.g., re-opening a file or re-issuing a request).
+//   - dst must be overwrite-safe: a restart writes the stream from its first
+//     byte again, so dst has to tolerate seeing a prefix twice (e.g., it
+//     rewinds or truncates, or deduplicates by offset).
+//
+// All other outcomes, including ErrWouldBlock / ErrMore returned per policy
+// without a partial write, are returned as in CopyPolicy. A nil policy is
+// treated as ReturnPolicy; a Yield that does not wait lets a persistently
+// blocked dst restart in a loop.
+func CopyRestartable(dst Writer, src func() Reader, policy SemanticPolicy) (written int64, err error) {
+	if src == nil {
+		return 0, ErrNilArgument
+	}
+	if policy == nil {
+		policy = ReturnPolicy{}
+	}
+	for {
+		written, err = copyBufferPolicy(dst, src(), nil, policy, nil)
+		if err != ErrNoSeeker {
+			return written, err
+		}
+		policy.Yield(OpCopyWrite)
+	}
+}
+
+// CopyFrames is like Copy for framed sinks that return ErrMore from Write to
+// mark a frame boundary. Each time dst returns ErrMore, onFrame is called with
+// the number of bytes dst accepted since the previous boundary, and the copy
+// continues with the rest of the chunk instead of returning.
+//
+// CopyFrames always uses the read/write loop (fast paths are skipped). An
+// ErrMore from dst that accepts no bytes when none are pending is returned to
+// the caller, as are all other errors; Seeker rollback applies as in Copy.
+// Bytes after the last boundary are counted in written but not reported to
+// onFrame.
+func CopyFrames(dst Writer, src Reader, onFrame func(frameBytes int64)) (written int64, err error) {
+	fw := &frameWriter{w: dst, onFrame: onFrame}
+	return copyBuffer(fw, src, nil, &CopyOpts{DisableFastPath: true})
+}
+
+// CopyFlushing is like Copy for buffering or compressing sinks (e.g.,
+// *gzip.Writer, *bufio.Writer, *BufWriter). When the copy stops with ErrMore,
+// marking a frame boundary, and dst implements Flush() error, dst.Flush is
+// called before returning so downstream sees the complete record promptly.
+//
+// The result is (written, ErrMore) after a successful flush, or the flush
+// error if Flush fails (it may itself be ErrWouldBlock). Other outcomes,
+// including EOF completion, do not flush; flushing or closing at the end of
+// the stream remains the caller's job.
+func CopyFlushing(dst Writer, src Reader) (written int64, err error) {
+	written, err = Copy(dst, src)
+	if IsMore(err) {
+		if fe := Flush(dst); fe != nil {
+			return written, fe
+		}
+	}
+	return written, err
+}
+
+// frameWriter turns writer-side ErrMore into onFrame callbacks.
+type frameWriter struct {
+	w       Writer
+	onFrame func(int64)
+	pending int64
+}
+
+func (f *frameWriter) Write(p []byte) (n int, err error) {
+	for n < len(p) {
+		nw, ew := f.w.Write(p[n:])
+		n += nw
+		f.pending += int64(nw)
+		if !IsMore(ew) || f.pending == 0 {
+			return n, ew
+		}
+		f.onFrame(f.pending)
+		f.pending = 0
+	}
+	return n, nil
 }
 
 // CopyBuffer is like Copy but stages through buf if needed.
@@ -64,11 +551,114 @@ func CopyPolicy(dst Writer, src Reader, policy SemanticPolicy) (written int64, e
 // Partial write recovery: same Seeker rollback semantics as Copy. Returns
 // ErrNoSeeker if src is not seekable and a partial write occurs with a
 // semantic error. See Copy documentation for details.
+//
+// Multi-shot ReaderFrom: if src does not implement WriterTo and dst
+// implements ReaderFromMulti, CopyBuffer keeps re-invoking dst.ReadFrom while
+// it returns ErrMore with progress, and returns once ReadFrom completes, fails,
+// would-block, or reports ErrMore without progress. Copy itself still returns
+// the first ErrMore to the caller.
 func CopyBuffer(dst Writer, src Reader, buf []byte) (written int64, err error) {
 	if buf != nil && len(buf) == 0 {
 		panic("empty buffer in CopyBuffer")
 	}
-	return copyBuffer(dst, src, buf)
+	if dst == nil || src == nil {
+		return 0, ErrNilArgument
+	}
+	if _, ok := src.(WriterTo); !ok {
+		if rf, ok := dst.(ReaderFromMulti); ok {
+			return readFromMulti(rf, src)
+		}
+	}
+	return copyBuffer(dst, src, buf, nil)
+}
+
+// CopyBufferWith is like CopyBuffer but configured by opts. If buf is nil, a
+// stack buffer is used. If buf has zero length, CopyBufferWith panics.
+func CopyBufferWith(dst Writer, src Reader, buf []byte, opts CopyOpts) (written int64, err error) {
+	if buf != nil && len(buf) == 0 {
+		panic("empty buffer in CopyBufferWith")
+	}
+	if opts.DetectConcurrentBuffer && buf != nil {
+		defer acquireBuffer(buf)()
+	}
+	return copyBuffer(dst, src, buf, &opts)
+}
+
+// buffersInUse holds the first element of every buffer acquired by a copy
+// running with CopyOpts.DetectConcurrentBuffer.
+var buffersInUse sync.Map // map[*byte]struct{}
+
+// acquireBuffer marks buf as in use and returns the func releasing it. It
+// panics if buf is already in use.
+func acquireBuffer(buf []byte) (release func()) {
+	key := &buf[0]
+	if _, loaded := buffersInUse.LoadOrStore(key, struct{}{}); loaded {
+		panic("iox: CopyBufferWith: buffer is already in use by a concurrent copy")
+	}
+	return func() { buffersInUse.Delete(key) }
+}
+
+// BufferInfo describes how a copy moved its data; see CopyBufferInfo.
+type BufferInfo struct {
+	// UsedFastPath is true when the copy delegated to src.WriteTo or
+	// dst.ReadFrom, which manage their own buffering.
+	UsedFastPath bool
+
+	// BufferSize is the size of the staging buffer used by the read/write
+	// loop, or 0 on a fast path.
+	BufferSize int
+}
+
+// CopyBufferInfo is like CopyBuffer but also reports which path was taken
+// and the effective staging buffer size. It is a diagnostics aid for tuning
+// buffer memory: with a nil buf the read/write loop uses a stack Buffer.
+func CopyBufferInfo(dst Writer, src Reader, buf []byte) (written int64, info BufferInfo, err error) {
+	if buf != nil && len(buf) == 0 {
+		panic("empty buffer in CopyBufferInfo")
+	}
+	info = bufferInfo(dst, src, buf)
+	written, err = CopyBuffer(dst, src, buf)
+	return written, info, err
+}
+
+// bufferInfo mirrors the path selection of CopyBuffer / copyBuffer.
+func bufferInfo(dst Writer, src Reader, buf []byte) BufferInfo {
+	if _, ok := src.(WriterTo); ok {
+		return BufferInfo{UsedFastPath: true}
+	}
+	if _, ok := dst.(ReaderFrom); ok {
+		return BufferInfo{UsedFastPath: true}
+	}
+	if buf == nil {
+		return BufferInfo{BufferSize: len(Buffer{})}
+	}
+	return BufferInfo{BufferSize: len(buf)}
+}
+
+// ReaderFromMulti is a ReaderFrom whose ErrMore means "this ReadFrom call made
+// progress and returned early; call ReadFrom again to continue" (e.g., a
+// multi-shot receive that surfaces each completion). CopyBuffer detects it and
+// loops on ErrMore instead of returning. MultiShot is a marker and is never
+// called.
+type ReaderFromMulti interface {
+	ReaderFrom
+	MultiShot()
+}
+
+func readFromMulti(rf ReaderFromMulti, src Reader) (written int64, err error) {
+	for {
+		n, e := rf.ReadFrom(src)
+		if n > 0 {
+			written += n
+		}
+		if IsMore(e) && n > 0 {
+			continue
+		}
+		if e == io.EOF {
+			e = nil
+		}
+		return written, e
+	}
 }
 
 // CopyBufferPolicy is like CopyBuffer but consults policy on semantic errors.
@@ -86,14 +676,40 @@ func CopyBufferPolicy(dst Writer, src Reader, buf []byte, policy SemanticPolicy)
 		panic("empty buffer in CopyBufferPolicy")
 	}
 	if policy == nil {
-		return copyBuffer(dst, src, buf)
+		return copyBuffer(dst, src, buf, nil)
+	}
+	return copyBufferPolicy(dst, src, buf, policy, nil)
+}
+
+// CopyPolicySize is like CopyBufferPolicy but takes a staging buffer size
+// instead of a buffer. Sizes up to len(Buffer) are served from an internal
+// pool; larger sizes are allocated per call. If bufSize <= 0, CopyPolicySize
+// panics, consistent with the empty-buffer panic of CopyBuffer.
+func CopyPolicySize(dst Writer, src Reader, bufSize int, policy SemanticPolicy) (written int64, err error) {
+	if bufSize <= 0 {
+		panic("non-positive buffer size in CopyPolicySize")
+	}
+	var buf []byte
+	if bufSize <= len(Buffer{}) {
+		b := bufferPool.Get().(*Buffer)
+		defer bufferPool.Put(b)
+		buf = b[:bufSize]
+	} else {
+		buf = make([]byte, bufSize)
 	}
-	return copyBufferPolicy(dst, src, buf, policy)
+	return CopyBufferPolicy(dst, src, buf, policy)
 }
 
+// bufferPool recycles staging Buffers for helpers that cannot use the stack.
+var bufferPool = sync.Pool{New: func() any { return new(Buffer) }}
+
 // CopyN copies n bytes (or until an error) from src to dst.
 // On return, written == n if and only if err == nil.
 //
+// If src implements both WriterTo and Seeker (e.g., *bytes.Reader, *os.File),
+// CopyN uses WriteTo with dst bounded to n bytes, then seeks src to just past
+// the last byte written, so src is never left over-read.
+//
 // iox semantics extension:
 //   - ErrWouldBlock / ErrMore may be returned when progress stops early;
 //     written may be > 0 and is the number of bytes already copied.
@@ -101,13 +717,21 @@ func CopyN(dst Writer, src Reader, n int64) (written int64, err error) {
 	if n <= 0 {
 		return 0, nil
 	}
+	if dst == nil || src == nil {
+		return 0, ErrNilArgument
+	}
 
-	lr := limitedReader{R: src, N: n}
+	if wt, s, start, ok := seekableWriterTo(src); ok {
+		return writeToN(dst, wt, s, start, n)
+	}
 
+	lr := LimitedReader{R: src, N: n}
 	if rf, ok := dst.(ReaderFrom); ok {
-		written, err = rf.ReadFrom(&lr)
+		if written, err = readFromN(rf, &lr, n); err == ErrOverRead {
+			return written, err
+		}
 	} else {
-		written, err = copyBuffer(dst, &lr, nil)
+		written, err = copyBuffer(dst, &lr, nil, nil)
 	}
 
 	if written == n {
@@ -123,6 +747,49 @@ func CopyN(dst Writer, src Reader, n int64) (written int64, err error) {
 	return written, err
 }
 
+// readFromN runs the ReaderFrom fast path of CopyN and CopyNBuffer over lr, which
+// is limited to n bytes. A ReaderFrom reporting a count other than the bytes
+// lr actually delivered has miscounted; the delivered count is returned with
+// ErrOverRead instead of corrupting the caller's bookkeeping.
+func readFromN(rf ReaderFrom, lr *LimitedReader, n int64) (written int64, err error) {
+	written, err = rf.ReadFrom(lr)
+	if delivered := n - lr.N; written != delivered {
+		return delivered, ErrOverRead
+	}
+	return written, err
+}
+
+// CopyNResume is like CopyN but also returns remaining = n - written, the
+// count to pass on the next call after a semantic stop. remaining is 0 on
+// success and whenever n <= 0.
+//
+//	for rem := n; rem > 0; {
+//		_, rem, err = iox.CopyNResume(dst, src, rem)
+//		// on ErrWouldBlock / ErrMore: wait, then loop
+//	}
+func CopyNResume(dst Writer, src Reader, n int64) (written, remaining int64, err error) {
+	written, err = CopyN(dst, src, n)
+	if n > written {
+		remaining = n - written
+	}
+	return written, remaining, err
+}
+
+// CopyNNotify is like CopyN but calls onComplete exactly once, before
+// returning, when the copy has written all n bytes (written == n with a nil
+// error). It is never called on a short or semantic return such as
+// io.ErrUnexpectedEOF, ErrWouldBlock, or ErrMore; resuming such a copy with
+// the remaining count and the same callback reports completion on the call
+// that finishes it. A nil onComplete is ignored. For n == 0 the copy is
+// trivially complete and onComplete is called.
+func CopyNNotify(dst Writer, src Reader, n int64, onComplete func()) (written int64, err error) {
+	written, err = CopyN(dst, src, n)
+	if err == nil && written == n && onComplete != nil {
+		onComplete()
+	}
+	return written, err
+}
+
 // CopyNPolicy is like CopyN but consults policy on semantic errors.
 //
 //   - nil policy: identical to CopyN
@@ -131,11 +798,71 @@ func CopyNPolicy(dst Writer, src Reader, n int64, policy SemanticPolicy) (writte
 	if n <= 0 {
 		return 0, nil
 	}
+	if dst == nil || src == nil {
+		return 0, ErrNilArgument
+	}
+	if policy == nil {
+		return CopyN(dst, src, n)
+	}
+	lr := LimitedReader{R: src, N: n}
+	return copyBufferPolicy(dst, &lr, nil, policy, nil)
+}
+
+// CopyNPolicyPooled is like CopyNPolicy but stages through a Buffer taken
+// from an internal pool (shared with CopyPolicySize), avoiding a per-call
+// staging allocation in hot loops.
+//
+// It keeps CopyN's exact-n contract: written == n if and only if err == nil,
+// and a source that ends before n bytes yields io.ErrUnexpectedEOF. A nil
+// policy is identical to CopyN.
+func CopyNPolicyPooled(dst Writer, src Reader, n int64, policy SemanticPolicy) (written int64, err error) {
+	if n <= 0 {
+		return 0, nil
+	}
+	if dst == nil || src == nil {
+		return 0, ErrNilArgument
+	}
 	if policy == nil {
 		return CopyN(dst, src, n)
 	}
-	lr := limitedReader{R: src, N: n}
-	return copyBufferPolicy(dst, &lr, nil, policy)
+	b := bufferPool.Get().(*Buffer)
+	defer bufferPool.Put(b)
+	lr := LimitedReader{R: src, N: n}
+	written, err = copyBufferPolicy(dst, &lr, b[:], policy, nil)
+	if written == n {
+		return n, nil
+	}
+	if err == nil || err == io.EOF {
+		return written, io.ErrUnexpectedEOF
+	}
+	return written, err
+}
+
+// CopyNMore is like CopyN for multi-shot sources: it keeps copying across
+// ErrMore boundaries (from either side, calling policy.Yield between
+// completions, as in CopyUntilEOF) and only returns once exactly n bytes
+// have been copied or progress stops. On return, written == n if and only if
+// err == nil; a source that ends before n bytes yields io.ErrUnexpectedEOF.
+//
+// ErrWouldBlock is decided by policy: PolicyRetry yields and retries,
+// PolicyReturn returns (written, ErrWouldBlock). A nil policy is treated as
+// ReturnPolicy.
+func CopyNMore(dst Writer, src Reader, n int64, policy SemanticPolicy) (written int64, err error) {
+	if n <= 0 {
+		return 0, nil
+	}
+	if policy == nil {
+		policy = ReturnPolicy{}
+	}
+	lr := LimitedReader{R: src, N: n}
+	written, err = copyBufferPolicy(dst, &lr, nil, retryMorePolicy{forwardingPolicy{policy}}, nil)
+	if written == n {
+		return n, nil
+	}
+	if err == nil || err == io.EOF {
+		return written, io.ErrUnexpectedEOF
+	}
+	return written, err
 }
 
 // CopyNBuffer is like CopyN but stages through buf if needed.
@@ -145,14 +872,22 @@ func CopyNBuffer(dst Writer, src Reader, n int64, buf []byte) (written int64, er
 	if n <= 0 {
 		return 0, nil
 	}
+	if dst == nil || src == nil {
+		return 0, ErrNilArgument
+	}
 	if buf != nil && len(buf) == 0 {
 		panic("empty buffer in CopyNBuffer")
 	}
-	lr := limitedReader{R: src, N: n}
+	if wt, s, start, ok := seekableWriterTo(src); ok {
+		return writeToN(dst, wt, s, start, n)
+	}
+	lr := LimitedReader{R: src, N: n}
 	if rf, ok := dst.(ReaderFrom); ok {
-		written, err = rf.ReadFrom(&lr)
+		if written, err = readFromN(rf, &lr, n); err == ErrOverRead {
+			return written, err
+		}
 	} else {
-		written, err = copyBuffer(dst, &lr, buf)
+		written, err = copyBuffer(dst, &lr, buf, nil)
 	}
 	if written == n {
 		return n, nil
@@ -170,22 +905,222 @@ func CopyNBufferPolicy(dst Writer, src Reader, n int64, buf []byte, policy Seman
 	if n <= 0 {
 		return 0, nil
 	}
+	if dst == nil || src == nil {
+		return 0, ErrNilArgument
+	}
 	if buf != nil && len(buf) == 0 {
 		panic("empty buffer in CopyNBufferPolicy")
 	}
 	if policy == nil {
 		return CopyNBuffer(dst, src, n, buf)
 	}
-	lr := limitedReader{R: src, N: n}
-	return copyBufferPolicy(dst, &lr, buf, policy)
+	lr := LimitedReader{R: src, N: n}
+	return copyBufferPolicy(dst, &lr, buf, policy, nil)
 }
 
-type limitedReader struct {
-	R Reader
+// CopyWriteN copies from src to dst until n bytes have been written to dst
+// or an error occurs. On return, written == n if and only if err == nil.
+//
+// Unlike CopyN, which limits the bytes read from src, CopyWriteN limits the
+// bytes delivered to dst: dst is wrapped in a limiting writer that accepts at
+// most n bytes in total and reports io.ErrShortWrite for anything beyond.
+// This bounds dst even when src over-produces through its WriterTo fast path,
+// and when dst implements ReaderFrom (ReadFrom only sees the first n bytes).
+//
+// Bytes produced by src beyond n may be consumed and discarded; use CopyN
+// when src must not be over-read.
+//
+// iox semantics extension:
+//   - ErrWouldBlock / ErrMore may be returned when progress stops early;
+//     written may be > 0 and is the number of bytes already written.
+func CopyWriteN(dst Writer, src Reader, n int64) (written int64, err error) {
+	if n <= 0 {
+		return 0, nil
+	}
+	if dst == nil || src == nil {
+		return 0, ErrNilArgument
+	}
+	lw := limitedWriter{W: dst, N: n}
+	if _, ok := dst.(ReaderFrom); ok {
+		written, err = copyBuffer(&limitedReaderFromWriter{lw}, src, nil, nil)
+	} else {
+		written, err = copyBuffer(&lw, src, nil, nil)
+	}
+	if written == n {
+		return n, nil
+	}
+	if err == nil || err == io.EOF {
+		return written, io.ErrUnexpectedEOF
+	}
+	return written, err
+}
+
+// CopyAt copies from src to dst until EOF or an error, writing each chunk
+// with dst.WriteAt at consecutive offsets starting at baseOff. It returns
+// the number of bytes written; the next free offset is baseOff+written.
+//
+// A short WriteAt without an error is reported as io.ErrShortWrite and a
+// (0, nil) read stops the copy, as in Copy.
+//
+// iox semantics extension:
+//   - ErrWouldBlock / ErrMore from src are returned after any data delivered
+//     with them has been written. Resume with CopyAt(dst, src, baseOff+written).
+//   - ErrWouldBlock / ErrMore from a partial WriteAt roll src back by the
+//     unwritten bytes if it implements io.Seeker, so the resume fills the gap;
+//     otherwise ErrNoSeeker is returned because those bytes are lost.
+func CopyAt(dst WriterAt, src Reader, baseOff int64) (written int64, err error) {
+	if dst == nil || src == nil {
+		return 0, ErrNilArgument
+	}
+	var stackBuf Buffer
+	buf := stackBuf[:]
+	off := baseOff
+	for {
+		nr, er := src.Read(buf)
+		if nr > 0 {
+			nw, ew := dst.WriteAt(buf[:nr], off)
+			if nw > 0 {
+				off += int64(nw)
+				written += int64(nw)
+			}
+			if ew != nil {
+				if nw < nr && IsSemantic(ew) {
+					if seeker, ok := src.(io.Seeker); ok {
+						if _, seekErr := seeker.Seek(int64(nw-nr), io.SeekCurrent); seekErr != nil {
+							return written, seekErr
+						}
+					} else {
+						return written, ErrNoSeeker
+					}
+				}
+				return written, ew
+			}
+			if nw != nr {
+				return written, io.ErrShortWrite
+			}
+		}
+		if er != nil {
+			if er == io.EOF {
+				return written, nil
+			}
+			return written, er
+		}
+		if nr == 0 {
+			return written, nil
+		}
+	}
+}
+
+// CopyFromAt copies n bytes from src, starting at offset off, to dst using
+// src.ReadAt. On return, written == n if and only if err == nil; if src ends
+// before n bytes, the error is io.ErrUnexpectedEOF.
+//
+// iox semantics extension:
+//   - ErrWouldBlock / ErrMore from ReadAt (e.g., asynchronous storage) are
+//     returned after any data delivered with them has been written. Resume
+//     with CopyFromAt(dst, src, off+written, n-written).
+func CopyFromAt(dst Writer, src ReaderAt, off, n int64) (written int64, err error) {
+	if n <= 0 {
+		return 0, nil
+	}
+	if dst == nil || src == nil {
+		return 0, ErrNilArgument
+	}
+	return CopyN(dst, NewSectionReader(src, off, n), n)
+}
+
+// seekableWriterTo reports whether src can serve a bounded copy through its
+// WriterTo fast path: it must also be a Seeker whose current offset is known,
+// so that any bytes WriteTo consumes beyond the bound can be given back.
+func seekableWriterTo(src Reader) (wt WriterTo, s Seeker, start int64, ok bool) {
+	if wt, ok = src.(WriterTo); !ok {
+		return nil, nil, 0, false
+	}
+	if s, ok = src.(Seeker); !ok {
+		return nil, nil, 0, false
+	}
+	start, err := s.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, nil, 0, false
+	}
+	return wt, s, start, true
+}
+
+// writeToN runs wt.WriteTo against dst bounded to n bytes, then repositions
+// the source to start+written so bytes read but not delivered are not lost.
+// It returns CopyN's result; a failed reposition is reported even when n
+// bytes were written, since the source offset is then unknown.
+func writeToN(dst Writer, wt WriterTo, s Seeker, start, n int64) (written int64, err error) {
+	lw := limitedWriter{W: dst, N: n}
+	written, err = wt.WriteTo(&lw)
+	if _, serr := s.Seek(start+written, io.SeekStart); serr != nil {
+		return written, serr
+	}
+	if written == n {
+		return n, nil
+	}
+	if err == nil || err == io.EOF {
+		return written, io.ErrUnexpectedEOF
+	}
+	return written, err
+}
+
+// limitedWriter writes to W but accepts at most N bytes in total.
+type limitedWriter struct {
+	W Writer
 	N int64
 }
 
-func (l *limitedReader) Read(p []byte) (n int, err error) {
+func (l *limitedWriter) Write(p []byte) (n int, err error) {
+	if l.N <= 0 {
+		return 0, io.ErrShortWrite
+	}
+	truncated := false
+	if int64(len(p)) > l.N {
+		p = p[:l.N]
+		truncated = true
+	}
+	n, err = l.W.Write(p)
+	if n > 0 {
+		l.N -= int64(n)
+	}
+	if err == nil && truncated {
+		err = io.ErrShortWrite
+	}
+	return n, err
+}
+
+// limitedReaderFromWriter is a limitedWriter whose W implements ReaderFrom.
+// ReadFrom keeps the fast path while bounding the source to the remaining N.
+type limitedReaderFromWriter struct{ limitedWriter }
+
+func (l *limitedReaderFromWriter) ReadFrom(r Reader) (n int64, err error) {
+	lr := LimitedReader{R: r, N: l.N}
+	n, err = l.W.(ReaderFrom).ReadFrom(&lr)
+	if n > 0 {
+		l.N -= n
+	}
+	return n, err
+}
+
+// LimitReader returns a Reader that reads from r but stops with EOF after n
+// bytes. The underlying implementation is a *LimitedReader.
+//
+// Unlike io.LimitReader, semantic errors from r (ErrWouldBlock / ErrMore) are
+// propagated unchanged, together with any partial data, and never masked.
+func LimitReader(r Reader, n int64) Reader { return &LimitedReader{R: r, N: n} }
+
+// LimitedReader reads from R but limits the amount of data returned to just N
+// bytes. Each call to Read updates N to reflect the new amount remaining.
+// Read returns EOF when N <= 0 or when the underlying R returns EOF.
+//
+// Errors from R, including ErrWouldBlock and ErrMore, are returned unchanged.
+type LimitedReader struct {
+	R Reader // underlying reader
+	N int64  // max bytes remaining
+}
+
+func (l *LimitedReader) Read(p []byte) (n int, err error) {
 	if l.N <= 0 {
 		return 0, io.EOF
 	}
@@ -199,34 +1134,288 @@ func (l *limitedReader) Read(p []byte) (n int, err error) {
 	return n, err
 }
 
+// ChunkReader returns a Reader that caps each Read at maxChunk bytes by
+// slicing p before calling r.Read. Unlike LimitReader, which caps the total,
+// it bounds the size of every single call, for downstreams that mis-handle
+// large reads. Results of r, including ErrWouldBlock and ErrMore, are passed
+// through unchanged. A maxChunk <= 0 returns r itself.
+func ChunkReader(r Reader, maxChunk int) Reader {
+	if maxChunk <= 0 {
+		return r
+	}
+	return chunkReader{r: r, max: maxChunk}
+}
+
+type chunkReader struct {
+	r   Reader
+	max int
+}
+
+func (c chunkReader) Read(p []byte) (int, error) {
+	if len(p) > c.max {
+		p = p[:c.max]
+	}
+	return c.r.Read(p)
+}
+
+// KeepAliveReader returns a KeepAlive reading from r, for continuous sources
+// such as log tailers that report the end of each burst with EOF but will
+// have more data later.
+//
+// EOF from r is reported as ErrMore, together with any bytes of that read, so
+// Copy returns at the burst boundary instead of completing and can be called
+// again when more data arrives. After Done, the next EOF is final and is
+// returned as EOF. Other results of r pass through unchanged.
+func KeepAliveReader(r Reader) *KeepAlive { return &KeepAlive{r: r} }
+
+// KeepAlive is the Reader returned by KeepAliveReader.
+type KeepAlive struct {
+	r    Reader
+	done atomic.Bool
+}
+
+// Read reads from the underlying Reader, mapping EOF to ErrMore until Done
+// has been called.
+func (k *KeepAlive) Read(p []byte) (n int, err error) {
+	n, err = k.r.Read(p)
+	if err == EOF && !k.done.Load() {
+		err = ErrMore
+	}
+	return n, err
+}
+
+// Done makes the next EOF from the underlying Reader final. It is safe to
+// call from another goroutine while a copy is running.
+func (k *KeepAlive) Done() { k.done.Store(true) }
+
+// ChunkWriter returns a Writer that splits each p into Write calls of at most
+// maxChunk bytes to w, for sinks that reject oversized writes. The returned n
+// aggregates the bytes w accepted.
+//
+// Write stops at the first error from w, including ErrWouldBlock and ErrMore,
+// and returns it with the count committed so far (which may end mid-chunk). A
+// chunk that w accepts only partly with a nil error stops with
+// io.ErrShortWrite. A maxChunk <= 0 returns w itself.
+func ChunkWriter(w Writer, maxChunk int) Writer {
+	if maxChunk <= 0 {
+		return w
+	}
+	return chunkWriter{w: w, max: maxChunk}
+}
+
+type chunkWriter struct {
+	w   Writer
+	max int
+}
+
+func (c chunkWriter) Write(p []byte) (n int, err error) {
+	for n < len(p) {
+		chunk := p[n:]
+		if len(chunk) > c.max {
+			chunk = chunk[:c.max]
+		}
+		nw, ew := c.w.Write(chunk)
+		n += nw
+		if ew != nil {
+			return n, ew
+		}
+		if nw != len(chunk) {
+			return n, io.ErrShortWrite
+		}
+	}
+	return n, nil
+}
+
+// DedupWriter returns a Writer for idempotent sinks that drops a frame
+// identical to the previous one: a Write(p) equal to the last payload w
+// accepted in full returns (len(p), nil) without calling w.
+//
+// Only whole frames count. A Write that w accepts partially (e.g., with
+// ErrWouldBlock) is returned unchanged and clears the remembered payload, so
+// retrying the rest and resending the frame are both forwarded. The last
+// payload is copied, costing one buffer of the largest frame.
+//
+// The returned Writer is not safe for concurrent use.
+func DedupWriter(w Writer) Writer { return &dedupWriter{w: w} }
+
+type dedupWriter struct {
+	w    Writer
+	last []byte
+	has  bool // last holds a fully accepted payload
+}
+
+func (d *dedupWriter) Write(p []byte) (n int, err error) {
+	if d.has && bytes.Equal(p, d.last) {
+		return len(p), nil
+	}
+	n, err = d.w.Write(p)
+	if n == len(p) {
+		d.last = append(d.last[:0], p...)
+		d.has = true
+	} else {
+		d.has = false
+	}
+	return n, err
+}
+
+// FrameReader returns a Reader that splits r into frames of frameSize bytes
+// and returns ErrMore together with the data that completes each frame. Reads
+// never cross a frame boundary. The final, possibly short, frame ends with
+// r's own EOF. Errors from r take precedence over the boundary ErrMore.
+//
+// It models framed multi-shot sources and is handy for exercising ErrMore
+// handling in tests. A frameSize <= 0 returns r itself.
+func FrameReader(r Reader, frameSize int) Reader {
+	if frameSize <= 0 {
+		return r
+	}
+	return &frameReader{r: r, size: frameSize}
+}
+
+type frameReader struct {
+	r    Reader
+	size int
+	off  int // bytes delivered in the current frame
+}
+
+func (f *frameReader) Read(p []byte) (n int, err error) {
+	if rem := f.size - f.off; len(p) > rem {
+		p = p[:rem]
+	}
+	n, err = f.r.Read(p)
+	f.off += n
+	if f.off == f.size {
+		f.off = 0
+		if err == nil {
+			err = ErrMore
+		}
+	}
+	return n, err
+}
+
+// FramedWriter returns a Writer that splits its output into frames of
+// frameSize bytes, the writer-side counterpart of FrameReader. A Write never
+// crosses a frame boundary: it accepts at most the bytes that complete the
+// current frame and returns them with ErrMore, leaving the rest of p for the
+// next Write. Bytes that leave a frame partial are accepted with a nil error.
+// Errors from w take precedence over the boundary ErrMore.
+//
+// Copy into a FramedWriter surfaces each boundary as ErrMore; CopyFrames
+// reports them through its callback. A frameSize <= 0 returns w itself.
+func FramedWriter(w Writer, frameSize int) Writer {
+	if frameSize <= 0 {
+		return w
+	}
+	return &framedWriter{w: w, size: frameSize}
+}
+
+type framedWriter struct {
+	w    Writer
+	size int
+	off  int // bytes accepted in the current frame
+}
+
+func (f *framedWriter) Write(p []byte) (n int, err error) {
+	if rem := f.size - f.off; len(p) > rem {
+		p = p[:rem]
+	}
+	n, err = f.w.Write(p)
+	f.off += n
+	if f.off == f.size {
+		f.off = 0
+		if err == nil {
+			err = ErrMore
+		}
+	}
+	return n, err
+}
+
+// ProgressGuard returns a Reader that detects a broken r stuck returning
+// (0, nil). It tolerates up to maxNoProgress consecutive (0, nil) reads and
+// returns ErrNoProgress (io.ErrNoProgress) on the next one. Any data or any
+// error, including ErrWouldBlock / ErrMore, resets the count. Reads with an
+// empty p are passed through and not counted.
+//
+// Use it in front of Copy when a silent (0, nil) "stop" would hide a reader
+// bug instead of reporting it.
+func ProgressGuard(r Reader, maxNoProgress int) Reader {
+	return &progressGuard{r: r, max: maxNoProgress}
+}
+
+type progressGuard struct {
+	r     Reader
+	max   int
+	count int
+}
+
+func (g *progressGuard) Read(p []byte) (n int, err error) {
+	n, err = g.r.Read(p)
+	if n > 0 || err != nil || len(p) == 0 {
+		g.count = 0
+		return n, err
+	}
+	g.count++
+	if g.count > g.max {
+		g.count = 0
+		return 0, ErrNoProgress
+	}
+	return 0, nil
+}
+
 // Buffer is the default stack buffer used by Copy when none is supplied.
 type Buffer [32 * 1024]byte
 
-func copyBuffer(dst Writer, src Reader, buf []byte) (written int64, err error) {
-	if wt, ok := src.(WriterTo); ok {
-		written, err = wt.WriteTo(dst)
-		if err == io.EOF {
-			err = nil
-		}
-		return written, err
+// copyBuffer is the default (non-policy) copy implementation.
+// opts may be nil, which selects the default behavior.
+func copyBuffer(dst Writer, src Reader, buf []byte, opts *CopyOpts) (written int64, err error) {
+	if dst == nil || src == nil {
+		return 0, ErrNilArgument
 	}
-	if rf, ok := dst.(ReaderFrom); ok {
-		written, err = rf.ReadFrom(src)
-		if err == io.EOF {
-			err = nil
+	var obs CopyObserver
+	if opts != nil {
+		obs = opts.Observer
+	}
+	if opts == nil || !opts.DisableFastPath {
+		rf, isRF := dst.(ReaderFrom)
+		if wt, ok := src.(WriterTo); ok && !(isRF && opts != nil && opts.PreferReaderFrom) {
+			written, err = wt.WriteTo(dst)
+			if obs != nil {
+				obs.ObserveOp(OpCopyWriterTo, int(written), err)
+			}
+			if err == io.EOF {
+				err = nil
+			}
+			return written, err
+		}
+		if isRF {
+			written, err = rf.ReadFrom(src)
+			if obs != nil {
+				obs.ObserveOp(OpCopyReaderFrom, int(written), err)
+			}
+			if err == io.EOF {
+				err = nil
+			}
+			return written, err
 		}
-		return written, err
 	}
 
-	var local Buffer
 	if buf == nil {
+		// Declared here so a caller-supplied buf does not pay for local,
+		// which escapes to the heap through src.Read.
+		var local Buffer
 		buf = local[:]
 	}
 
 	for {
 		nr, er := src.Read(buf)
+		if obs != nil {
+			obs.ObserveOp(OpCopyRead, nr, er)
+		}
 		if nr > 0 {
 			nw, ew := dst.Write(buf[:nr])
+			if obs != nil {
+				obs.ObserveOp(OpCopyWrite, nw, ew)
+			}
 			if nw > 0 {
 				written += int64(nw)
 			}
@@ -270,89 +1459,58 @@ func copyBuffer(dst Writer, src Reader, buf []byte) (written int64, err error) {
 }
 
 // copyBufferPolicy is a policy-aware copy implementation.
-// policy is guaranteed non-nil by callers.
-func copyBufferPolicy(dst Writer, src Reader, buf []byte, policy SemanticPolicy) (written int64, err error) {
+// policy is guaranteed non-nil by callers; opts may be nil.
+func copyBufferPolicy(dst Writer, src Reader, buf []byte, policy SemanticPolicy, opts *CopyOpts) (written int64, err error) {
+	if dst == nil || src == nil {
+		return 0, ErrNilArgument
+	}
+	var obs CopyObserver
+	if opts != nil {
+		obs = opts.Observer
+	}
 	// Fast paths with policy awareness: loop and consult policy on semantic errors.
-	if wt, ok := src.(WriterTo); ok {
-		var total int64
-		for {
-			n, e := wt.WriteTo(dst)
-			if n > 0 {
-				total += n
-			}
-			if e == nil {
-				return total, nil
-			}
-			if e == io.EOF {
-				return total, nil
-			}
-			if e == ErrWouldBlock {
-				if policy.OnWouldBlock(OpCopyWriterTo) == PolicyRetry {
-					policy.Yield(OpCopyWriterTo)
-					continue
-				}
-				return total, ErrWouldBlock
-			}
-			if e == ErrMore {
-				if policy.OnMore(OpCopyWriterTo) == PolicyRetry {
-					policy.Yield(OpCopyWriterTo)
-					continue
-				}
-				return total, ErrMore
-			}
-			return total, e
-		}
+	fast := opts == nil || !opts.DisableFastPath
+	rf, isRF := dst.(ReaderFrom)
+	isRF = isRF && fast
+	if wt, ok := src.(WriterTo); ok && fast && !(isRF && opts != nil && opts.PreferReaderFrom) {
+		return writeToPolicy(dst, wt, policy, obs)
 	}
-	if rf, ok := dst.(ReaderFrom); ok {
-		var total int64
-		for {
-			n, e := rf.ReadFrom(src)
-			if n > 0 {
-				total += n
-			}
-			if e == nil {
-				return total, nil
-			}
-			if e == io.EOF {
-				return total, nil
-			}
-			if e == ErrWouldBlock {
-				if policy.OnWouldBlock(OpCopyReaderFrom) == PolicyRetry {
-					policy.Yield(OpCopyReaderFrom)
-					continue
-				}
-				return total, ErrWouldBlock
-			}
-			if e == ErrMore {
-				if policy.OnMore(OpCopyReaderFrom) == PolicyRetry {
-					policy.Yield(OpCopyReaderFrom)
-					continue
-				}
-				return total, ErrMore
-			}
-			return total, e
-		}
+	if isRF {
+		return readFromPolicy(rf, src, policy, obs)
 	}
 
-	var local Buffer
+	np, _ := policy.(NoProgressPolicy)
+	stalls := 0
+
 	if buf == nil {
+		var local Buffer
 		buf = local[:]
 	}
 
 	for {
 		nr, er := src.Read(buf)
+		if obs != nil {
+			obs.ObserveOp(OpCopyRead, nr, er)
+		}
+		progress(policy, OpCopyRead, nr)
 		if nr > 0 {
+			stalls = 0
 			// write possibly in multiple attempts if writer would-block/more
 			off := 0
 			for off < nr {
 				nw, ew := dst.Write(buf[off:nr])
+				if obs != nil {
+					obs.ObserveOp(OpCopyWrite, nw, ew)
+				}
+				progress(policy, OpCopyWrite, nw)
 				if nw > 0 {
 					written += int64(nw)
 					off += nw
+					stalls = 0
 				}
 				if ew != nil {
-					if ew == ErrWouldBlock {
-						if policy.OnWouldBlock(OpCopyWrite) == PolicyRetry {
+					if IsWouldBlock(ew) {
+						if policy.OnWouldBlock(OpCopyWrite) == PolicyRetry && !stalled(np, OpCopyWrite, int64(nw), &stalls) {
 							policy.Yield(OpCopyWrite)
 							continue
 						}
@@ -367,10 +1525,10 @@ func copyBufferPolicy(dst Writer, src Reader, buf []byte, policy SemanticPolicy)
 								return written, ErrNoSeeker
 							}
 						}
-						return written, ErrWouldBlock
+						return written, ew
 					}
-					if ew == ErrMore {
-						if policy.OnMore(OpCopyWrite) == PolicyRetry {
+					if IsMore(ew) {
+						if policy.OnMore(OpCopyWrite) == PolicyRetry && !stalled(np, OpCopyWrite, int64(nw), &stalls) {
 							policy.Yield(OpCopyWrite)
 							continue
 						}
@@ -385,7 +1543,7 @@ func copyBufferPolicy(dst Writer, src Reader, buf []byte, policy SemanticPolicy)
 								return written, ErrNoSeeker
 							}
 						}
-						return written, ErrMore
+						return written, ew
 					}
 					return written, ew
 				}
@@ -399,19 +1557,19 @@ func copyBufferPolicy(dst Writer, src Reader, buf []byte, policy SemanticPolicy)
 			if er == io.EOF {
 				return written, nil
 			}
-			if er == ErrWouldBlock {
-				if policy.OnWouldBlock(OpCopyRead) == PolicyRetry {
+			if IsWouldBlock(er) {
+				if policy.OnWouldBlock(OpCopyRead) == PolicyRetry && !stalled(np, OpCopyRead, int64(nr), &stalls) {
 					policy.Yield(OpCopyRead)
 					continue
 				}
-				return written, ErrWouldBlock
+				return written, er
 			}
-			if er == ErrMore {
-				if policy.OnMore(OpCopyRead) == PolicyRetry {
+			if IsMore(er) {
+				if policy.OnMore(OpCopyRead) == PolicyRetry && !stalled(np, OpCopyRead, int64(nr), &stalls) {
 					policy.Yield(OpCopyRead)
 					continue
 				}
-				return written, ErrMore
+				return written, er
 			}
 			return written, er
 		}
@@ -421,3 +1579,94 @@ func copyBufferPolicy(dst Writer, src Reader, buf []byte, policy SemanticPolicy)
 		}
 	}
 }
+
+// writeToPolicy runs the WriterTo fast path, repeating wt.WriteTo while
+// policy retries its semantic errors. obs, if non-nil, sees every attempt.
+func writeToPolicy(dst Writer, wt WriterTo, policy SemanticPolicy, obs CopyObserver) (int64, error) {
+	np, _ := policy.(NoProgressPolicy)
+	stalls := 0
+	var total int64
+	for {
+		n, e := wt.WriteTo(dst)
+		if obs != nil {
+			obs.ObserveOp(OpCopyWriterTo, int(n), e)
+		}
+		progress(policy, OpCopyWriterTo, int(n))
+		if n > 0 {
+			total += n
+		}
+		if e == nil {
+			return total, nil
+		}
+		if e == io.EOF {
+			return total, nil
+		}
+		if IsWouldBlock(e) {
+			if policy.OnWouldBlock(OpCopyWriterTo) == PolicyRetry && !stalled(np, OpCopyWriterTo, n, &stalls) {
+				policy.Yield(OpCopyWriterTo)
+				continue
+			}
+			return total, e
+		}
+		if IsMore(e) {
+			if policy.OnMore(OpCopyWriterTo) == PolicyRetry && !stalled(np, OpCopyWriterTo, n, &stalls) {
+				policy.Yield(OpCopyWriterTo)
+				continue
+			}
+			return total, e
+		}
+		return total, e
+	}
+}
+
+// readFromPolicy runs the ReaderFrom fast path, repeating rf.ReadFrom while
+// policy retries its semantic errors. obs, if non-nil, sees every attempt.
+func readFromPolicy(rf ReaderFrom, src Reader, policy SemanticPolicy, obs CopyObserver) (int64, error) {
+	np, _ := policy.(NoProgressPolicy)
+	stalls := 0
+	var total int64
+	for {
+		n, e := rf.ReadFrom(src)
+		if obs != nil {
+			obs.ObserveOp(OpCopyReaderFrom, int(n), e)
+		}
+		progress(policy, OpCopyReaderFrom, int(n))
+		if n > 0 {
+			total += n
+		}
+		if e == nil {
+			return total, nil
+		}
+		if e == io.EOF {
+			return total, nil
+		}
+		if IsWouldBlock(e) {
+			if policy.OnWouldBlock(OpCopyReaderFrom) == PolicyRetry && !stalled(np, OpCopyReaderFrom, n, &stalls) {
+				policy.Yield(OpCopyReaderFrom)
+				continue
+			}
+			return total, e
+		}
+		if IsMore(e) {
+			if policy.OnMore(OpCopyReaderFrom) == PolicyRetry && !stalled(np, OpCopyReaderFrom, n, &stalls) {
+				policy.Yield(OpCopyReaderFrom)
+				continue
+			}
+			return total, e
+		}
+		return total, e
+	}
+}
+
+// stalled records one retry decision for the NoProgressPolicy hook. n is the
+// progress made by the attempt that produced the semantic error: progress
+// resets the consecutive count, no progress increments it and asks np (if
+// any) whether to keep retrying. It reports true when np says PolicyReturn.
+func stalled(np NoProgressPolicy, op Op, n int64, count *int) bool {
+	if n > 0 {
+		*count = 0
+		return false
+	}
+	*count++
+	return np != nil && np.OnNoProgress(op, *count) == PolicyReturn
+}
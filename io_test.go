@@ -6,7 +6,9 @@ package iox_test
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"errors"
+	"fmt"
 	"io"
 	"testing"
 
@@ -28,12 +30,16 @@ func (r *zeroThenNilReader) Read(p []byte) (int, error) {
 	r.called = true
 	return 0, nil
 }
+// scriptedReader returns one scripted completion per Read, then EOF.
 type scriptedReader struct {
-	steps []struct {
-		b   []byte
-		err error
-	}
-	i int
+	steps []scriptStep
+	i     int
+}
+// scriptStep is one completion of a scriptedReader: b is delivered together
+// with err.
+type scriptStep = struct {
+	b   []byte
+	err error
 }
 func (s *scriptedReader) Read(p []byte) (int, error) {
 	if s.i >= len(s.steps) {
@@ -41,11 +47,7 @@ func (s *scriptedReader) Read(p []byte) (int, error) {
 	}
 	st := s.steps[s.i]
 	s.i++
-	if len(st.b) > 0 {
-		n := copy(p, st.b)
-		return n, nil
-	}
-	return 0, st.err
+	return copy(p, st.b), st.err
 }
 type shortWriter struct{ limit int }
 func (w shortWriter) Write(p []byte) (int, error) {
@@ -99,16 +101,23 @@ type rfWriter struct {
 	err error
 }
 func (w rfWriter) Write(p []byte) (int, error) { return len(p), nil }
-func (w rfWriter) ReadFrom(r iox.Reader) (int64, error) { return w.n, w.err }
+// ReadFrom consumes up to n bytes from r and reports n, whatever r held.
+func (w rfWriter) ReadFrom(r iox.Reader) (int64, error) {
+	io.CopyN(io.Discard, r, w.n)
+	return w.n, w.err
+}
 type noWTReader struct{ r *bytes.Reader }
 func (r noWTReader) Read(p []byte) (int, error) { return r.r.Read(p) }
 // ReaderFrom variants used to drive CopyN post-conditions.
 type rfShortNil struct{}
 func (rfShortNil) Write(p []byte) (int, error)          { return len(p), nil }
-func (rfShortNil) ReadFrom(r iox.Reader) (int64, error) { return 3, nil }
+func (rfShortNil) ReadFrom(r iox.Reader) (int64, error) { return io.CopyN(io.Discard, r, 3) }
 type rfShortEOF struct{}
 func (rfShortEOF) Write(p []byte) (int, error)          { return len(p), nil }
-func (rfShortEOF) ReadFrom(r iox.Reader) (int64, error) { return 4, iox.EOF }
+func (rfShortEOF) ReadFrom(r iox.Reader) (int64, error) {
+	n, _ := io.CopyN(io.Discard, r, 4)
+	return n, iox.EOF
+}
 type dataThenErrReader struct {
 	data []byte
 	err  error
@@ -122,7 +131,7 @@ func (r *dataThenErrReader) Read(p []byte) (int, error) {
 	n := copy(p, r.data)
 	return n, r.err
 }
-// A ReaderFrom that actually consumes from the supplied reader, to exercise limitedReader.
+// A ReaderFrom that actually consumes from the supplied reader, to exercise LimitedReader.
 type rfConsume struct{}
 func (rfConsume) Write(p []byte) (int, error) { return len(p), nil }
 func (rfConsume) ReadFrom(r iox.Reader) (int64, error) {
@@ -580,6 +589,52 @@ func TestCopyN_ReaderFrom_ExactEOF_Ignored(t *testing.T) {
 		t.Fatalf("n=%d", n)
 	}
 }
+func TestCopyN_ReaderFrom_OverRead(t *testing.T) {
+	src := noWTReader{r: bytes.NewReader([]byte("ignored"))}
+	n, err := iox.CopyN(rfWriter{n: 9}, src, 5)
+	if !errors.Is(err, iox.ErrOverRead) || n != 5 {
+		t.Fatalf("want (5, ErrOverRead) got (%d, %v)", n, err)
+	}
+	src = noWTReader{r: bytes.NewReader([]byte("ignored"))}
+	n, err = iox.CopyNBuffer(rfWriter{n: 6, err: iox.EOF}, src, 5, nil)
+	if !errors.Is(err, iox.ErrOverRead) || n != 5 {
+		t.Fatalf("buffer: want (5, ErrOverRead) got (%d, %v)", n, err)
+	}
+
+	// A claim below n that still exceeds what was actually taken from src.
+	src = noWTReader{r: bytes.NewReader([]byte("ignored"))}
+	n, err = iox.CopyN(rfClaim{take: 2, claim: 4}, src, 5)
+	if !errors.Is(err, iox.ErrOverRead) || n != 2 {
+		t.Fatalf("under-delivered: want (2, ErrOverRead) got (%d, %v)", n, err)
+	}
+
+	// A ReaderFrom that drains all n bytes and still over-reports.
+	n, err = iox.CopyN(&overReportWriter{extra: 3}, noWTReader{r: bytes.NewReader([]byte("0123456789"))}, 5)
+	if !errors.Is(err, iox.ErrOverRead) || n != 5 {
+		t.Fatalf("drained: want (5, ErrOverRead) got (%d, %v)", n, err)
+	}
+	n, err = iox.CopyNBuffer(&overReportWriter{extra: 3}, noWTReader{r: bytes.NewReader([]byte("0123456789"))}, 5, nil)
+	if !errors.Is(err, iox.ErrOverRead) || n != 5 {
+		t.Fatalf("drained buffer: want (5, ErrOverRead) got (%d, %v)", n, err)
+	}
+}
+// rfClaim takes take bytes from r but reports claim.
+type rfClaim struct{ take, claim int64 }
+func (rfClaim) Write(p []byte) (int, error) { return len(p), nil }
+func (w rfClaim) ReadFrom(r iox.Reader) (int64, error) {
+	io.CopyN(io.Discard, r, w.take)
+	return w.claim, nil
+}
+// overReportWriter reads everything it is given but adds extra to the count.
+type overReportWriter struct {
+	buf   bytes.Buffer
+	extra int64
+}
+func (w *overReportWriter) Write(p []byte) (int, error) { return w.buf.Write(p) }
+func (w *overReportWriter) ReadFrom(r io.Reader) (int64, error) {
+	n, err := w.buf.ReadFrom(r)
+	return n + w.extra, err
+}
 func TestCopyBuffer_PanicOnEmpty(t *testing.T) {
 	defer func() {
 		if r := recover(); r == nil {
@@ -590,6 +645,22 @@ func TestCopyBuffer_PanicOnEmpty(t *testing.T) {
 	src := bytes.NewBufferString("x")
 	_, _ = iox.CopyBuffer(&dst, src, make([]byte, 0))
 }
+func TestCopy_ZeroLengthCompletionReturnsImmediately(t *testing.T) {
+	steps := []scriptStep{{}, {b: []byte("late")}}
+	var dst sliceWriter
+	src := &scriptedReader{steps: steps}
+	if n, err := iox.Copy(&dst, src); n != 0 || err != nil || src.i != 1 {
+		t.Fatalf("Copy=(%d,%v) reads=%d want (0,nil) after one read", n, err, src.i)
+	}
+	pol := &recPolicy{onWB: map[iox.Op]iox.PolicyAction{iox.OpCopyRead: iox.PolicyRetry}}
+	src = &scriptedReader{steps: steps}
+	if n, err := iox.CopyPolicy(&dst, src, pol); n != 0 || err != nil || src.i != 1 {
+		t.Fatalf("CopyPolicy=(%d,%v) reads=%d want (0,nil) after one read", n, err, src.i)
+	}
+	if len(dst.data) != 0 || len(pol.yields) != 0 {
+		t.Fatalf("zero-length completion had effects: dst=%q yields=%v", dst.data, pol.yields)
+	}
+}
 func TestCopy_ZeroThenNil(t *testing.T) {
 	var r zeroThenNilReader
 	var dst bytes.Buffer
@@ -840,7 +911,7 @@ func TestTeeWriter_TeeShortWrite(t *testing.T) {
 // additional helpers and tests
 type rfN struct{ n int64 }
 func (rfN) Write(p []byte) (int, error)            { return len(p), nil }
-func (w rfN) ReadFrom(r iox.Reader) (int64, error) { return w.n, nil }
+func (w rfN) ReadFrom(r iox.Reader) (int64, error) { return io.CopyN(io.Discard, r, w.n) }
 type shortSideWriter struct{}
 func (shortSideWriter) Write(p []byte) (int, error) {
 	if len(p) == 0 {
@@ -2125,3 +2196,1605 @@ func TestCopy_ReturnOnErrMore_AcrossCalls(t *testing.T) {
 		t.Fatalf("second: n=%d dst=%q", n2, dst.String())
 	}
 }
+
+// moreChunksReader returns each chunk with ErrMore except the last, which is
+// returned with nil, followed by EOF.
+type moreChunksReader struct {
+	chunks [][]byte
+	i      int
+}
+
+func (r *moreChunksReader) Read(p []byte) (int, error) {
+	if r.i >= len(r.chunks) {
+		return 0, iox.EOF
+	}
+	n := copy(p, r.chunks[r.i])
+	r.i++
+	if r.i < len(r.chunks) {
+		return n, iox.ErrMore
+	}
+	return n, nil
+}
+
+func TestCopyPolicyCounts(t *testing.T) {
+	src := &moreChunksReader{chunks: [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("d")}}
+	retryMore := iox.PolicyFunc{MoreFunc: func(iox.Op) iox.PolicyAction { return iox.PolicyRetry }}
+	var dst sliceWriter
+	n, more, err := iox.CopyPolicyCounts(&dst, src, retryMore)
+	if n != 4 || more != 3 || err != nil || string(dst.data) != "abcd" {
+		t.Fatalf("n=%d more=%d err=%v dst=%q", n, more, err, dst.data)
+	}
+
+	// A boundary that ends the copy is not counted.
+	src = &moreChunksReader{chunks: [][]byte{[]byte("a"), []byte("b")}}
+	n, more, err = iox.CopyPolicyCounts(&sliceWriter{}, src, iox.YieldPolicy{})
+	if n != 1 || more != 0 || err != iox.ErrMore {
+		t.Fatalf("return: n=%d more=%d err=%v", n, more, err)
+	}
+}
+
+func TestCopyUntilEOF_DrainsAcrossErrMore(t *testing.T) {
+	src := &moreChunksReader{chunks: [][]byte{[]byte("ab"), []byte("cd"), []byte("ef")}}
+	var dst sliceWriter
+	pol := &recPolicy{}
+	n, err := iox.CopyUntilEOF(&dst, src, pol)
+	if err != nil || n != 6 || string(dst.data) != "abcdef" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, string(dst.data))
+	}
+	if len(pol.yields) != 2 || pol.yields[0] != iox.OpCopyRead || pol.yields[1] != iox.OpCopyRead {
+		t.Fatalf("yields=%v", pol.yields)
+	}
+}
+
+func TestCopyUntilEOF_WouldBlockFollowsPolicy(t *testing.T) {
+	var dst sliceWriter
+	n, err := iox.CopyUntilEOF(&dst, &wbThenDataReader{data: []byte("xy")}, nil)
+	if !errors.Is(err, iox.ErrWouldBlock) || n != 0 {
+		t.Fatalf("want (0, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	dst.data = nil
+	n, err = iox.CopyUntilEOF(&dst, &wbThenDataReader{data: []byte("xy")}, iox.YieldPolicy{})
+	if err != nil || n != 2 || string(dst.data) != "xy" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, string(dst.data))
+	}
+}
+
+func TestCopyUntilEOF_NoProgressPolicyStopsStuckSource(t *testing.T) {
+	p := &noProgressCap{max: 3}
+	n, err := iox.CopyUntilEOF(&sliceWriter{}, errReaderAlwaysWB{}, p)
+	if n != 0 || err != iox.ErrWouldBlock {
+		t.Fatalf("want (0, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	if len(p.counts) != 3 || p.counts[2] != 3 {
+		t.Fatalf("counts=%v want [1 2 3]", p.counts)
+	}
+}
+
+func TestCopyUntilEOF_WriterToFastPath(t *testing.T) {
+	src := &wtMoreThenOK{data: []byte("fast")}
+	var dst bytes.Buffer
+	n, err := iox.CopyUntilEOF(&dst, src, nil)
+	if err != nil || n != 4 || dst.String() != "fast" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, dst.String())
+	}
+}
+
+// wrappedWBWriter accepts up to partial bytes per call and returns an
+// annotated ErrWouldBlock whenever it cannot accept the whole slice.
+type wrappedWBWriter struct {
+	partial int
+	blocked bool
+	buf     []byte
+}
+
+func (w *wrappedWBWriter) Write(p []byte) (int, error) {
+	if w.blocked {
+		w.blocked = false
+		w.buf = append(w.buf, p...)
+		return len(p), nil
+	}
+	w.blocked = true
+	n := min(w.partial, len(p))
+	w.buf = append(w.buf, p[:n]...)
+	return n, iox.WrapWouldBlock("sink")
+}
+
+func TestCopy_WrappedWouldBlock_PartialWrite_SeekerRollback(t *testing.T) {
+	src := &workingSeeker{data: []byte("hello")}
+	dst := &wrappedWBWriter{partial: 2}
+	n, err := iox.Copy(dst, src)
+	if !iox.IsWouldBlock(err) || err.Error() != "sink: io: would block" || n != 2 {
+		t.Fatalf("want (2, wrapped ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	// The source was rolled back to the first unwritten byte.
+	n, err = iox.Copy(dst, src)
+	if err != nil || n != 3 || string(dst.buf) != "hello" {
+		t.Fatalf("second copy: n=%d err=%v dst=%q", n, err, string(dst.buf))
+	}
+}
+
+func TestCopy_WrappedWouldBlock_PartialWrite_NoSeeker(t *testing.T) {
+	src := &plainReader{data: []byte("hello")}
+	n, err := iox.Copy(&wrappedWBWriter{partial: 2}, src)
+	if !errors.Is(err, iox.ErrNoSeeker) || n != 2 {
+		t.Fatalf("want (2, ErrNoSeeker) got (%d, %v)", n, err)
+	}
+}
+
+func TestCopyPolicy_WrappedSemantics_TreatedAsSentinels(t *testing.T) {
+	// Write side: wrapped ErrWouldBlock is retried under YieldPolicy.
+	dst := &wrappedWBWriter{partial: 1}
+	n, err := iox.CopyPolicy(dst, &plainReader{data: []byte("abc")}, iox.YieldPolicy{})
+	if err != nil || n != 3 || string(dst.buf) != "abc" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, string(dst.buf))
+	}
+	// Read side: wrapped ErrMore is returned unchanged under YieldPolicy.
+	more := iox.WrapMore("ring")
+	var out sliceWriter
+	n, err = iox.CopyPolicy(&out, &dataThenErrReader{data: []byte("xy"), err: more}, iox.YieldPolicy{})
+	if err != more || n != 2 {
+		t.Fatalf("want (2, %v) got (%d, %v)", more, n, err)
+	}
+}
+
+func TestLimitReader_EnforcedAcrossReads(t *testing.T) {
+	lr := iox.LimitReader(bytes.NewReader([]byte("abcdefgh")), 5)
+	var got []byte
+	var b [2]byte
+	for {
+		n, err := lr.Read(b[:])
+		got = append(got, b[:n]...)
+		if err == iox.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+	}
+	if string(got) != "abcde" {
+		t.Fatalf("got %q", string(got))
+	}
+}
+
+func TestLimitReader_PropagatesErrMoreWithPartialData(t *testing.T) {
+	lr := iox.LimitReader(&dataThenErrReader{data: []byte("abc"), err: iox.ErrMore}, 10)
+	var b [8]byte
+	n, err := lr.Read(b[:])
+	if !errors.Is(err, iox.ErrMore) || n != 3 {
+		t.Fatalf("want (3, ErrMore) got (%d, %v)", n, err)
+	}
+	if l := lr.(*iox.LimitedReader); l.N != 7 {
+		t.Fatalf("remaining N=%d want 7", l.N)
+	}
+	lr = iox.LimitReader(errReader{err: iox.ErrWouldBlock}, 10)
+	if n, err := lr.Read(b[:]); !errors.Is(err, iox.ErrWouldBlock) || n != 0 {
+		t.Fatalf("want (0, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+}
+
+func TestLimitReader_ExactlyNThenEOF(t *testing.T) {
+	lr := iox.LimitReader(bytes.NewReader([]byte("abcdef")), 3)
+	var b [3]byte
+	if n, err := lr.Read(b[:]); err != nil || n != 3 {
+		t.Fatalf("want (3, nil) got (%d, %v)", n, err)
+	}
+	if n, err := lr.Read(b[:]); err != iox.EOF || n != 0 {
+		t.Fatalf("want (0, EOF) got (%d, %v)", n, err)
+	}
+}
+
+func TestCopyWriteN_WriterToOverProduces(t *testing.T) {
+	var dst sliceWriter
+	n, err := iox.CopyWriteN(&dst, wtReader{n: 10}, 4)
+	if err != nil || n != 4 || string(dst.data) != "xxxx" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, string(dst.data))
+	}
+}
+
+func TestCopyWriteN_ReaderFromDst(t *testing.T) {
+	var dst bytes.Buffer
+	src := noWTReader{bytes.NewReader([]byte("abcdefgh"))}
+	n, err := iox.CopyWriteN(&dst, src, 5)
+	if err != nil || n != 5 || dst.String() != "abcde" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, dst.String())
+	}
+	// Both fast-path interfaces: WriterTo wins, the limit still holds.
+	dst.Reset()
+	n, err = iox.CopyWriteN(&dst, bytes.NewReader([]byte("abcdefgh")), 3)
+	if err != nil || n != 3 || dst.String() != "abc" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, dst.String())
+	}
+}
+
+func TestCopyWriteN_SlowPathAndShortSource(t *testing.T) {
+	var dst sliceWriter
+	n, err := iox.CopyWriteN(&dst, &plainReader{data: []byte("abcdef")}, 4)
+	if err != nil || n != 4 || string(dst.data) != "abcd" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, string(dst.data))
+	}
+	dst.data = nil
+	n, err = iox.CopyWriteN(&dst, &plainReader{data: []byte("ab")}, 4)
+	if !errors.Is(err, iox.ErrUnexpectedEOF) || n != 2 {
+		t.Fatalf("want (2, ErrUnexpectedEOF) got (%d, %v)", n, err)
+	}
+	if n, err := iox.CopyWriteN(&dst, &plainReader{}, 0); n != 0 || err != nil {
+		t.Fatalf("want (0, nil) got (%d, %v)", n, err)
+	}
+}
+
+func TestCopyWriteN_PropagatesWouldBlock(t *testing.T) {
+	dst := &partialWBWriter{partial: 2}
+	n, err := iox.CopyWriteN(dst, &workingSeeker{data: []byte("abcdef")}, 4)
+	if !errors.Is(err, iox.ErrWouldBlock) || n != 2 {
+		t.Fatalf("want (2, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+}
+
+// panicWT panics if its WriterTo fast path is used.
+type panicWT struct{ r *bytes.Reader }
+
+func (p panicWT) Read(b []byte) (int, error)      { return p.r.Read(b) }
+func (panicWT) WriteTo(iox.Writer) (int64, error) { panic("WriteTo must not be called") }
+
+// panicRF panics if its ReaderFrom fast path is used.
+type panicRF struct{ sliceWriter }
+
+func (*panicRF) ReadFrom(iox.Reader) (int64, error) { panic("ReadFrom must not be called") }
+
+func TestCopyWith_DisableFastPath_SkipsWriterTo(t *testing.T) {
+	var dst sliceWriter
+	n, err := iox.CopyWith(&dst, panicWT{bytes.NewReader([]byte("slow"))}, iox.CopyOpts{DisableFastPath: true})
+	if err != nil || n != 4 || string(dst.data) != "slow" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, string(dst.data))
+	}
+}
+
+func TestCopyWith_DisableFastPath_SkipsReaderFrom(t *testing.T) {
+	dst := &panicRF{}
+	n, err := iox.CopyWith(dst, &plainReader{data: []byte("path")}, iox.CopyOpts{DisableFastPath: true})
+	if err != nil || n != 4 || string(dst.data) != "path" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, string(dst.data))
+	}
+}
+
+func TestCopyWith_DisableFastPath_MatchesSlowPath(t *testing.T) {
+	// bytes.Reader implements WriterTo and Seeker; with the fast path disabled
+	// a partial would-block write is rolled back exactly as in the slow path.
+	src := bytes.NewReader([]byte("hello"))
+	dst := &partialWBWriter{partial: 2}
+	n, err := iox.CopyWith(dst, src, iox.CopyOpts{DisableFastPath: true})
+	if !errors.Is(err, iox.ErrWouldBlock) || n != 2 || src.Len() != 3 {
+		t.Fatalf("n=%d err=%v remaining=%d", n, err, src.Len())
+	}
+	// The zero value keeps the fast paths.
+	var out bytes.Buffer
+	n, err = iox.CopyWith(&out, wtReader{n: 3}, iox.CopyOpts{})
+	if err != nil || n != 3 || out.String() != "xxx" {
+		t.Fatalf("n=%d err=%v out=%q", n, err, out.String())
+	}
+}
+
+// opEvent is a single CopyObserver notification.
+type opEvent struct {
+	op  iox.Op
+	n   int
+	err error
+}
+
+// recObserver records every observed step.
+type recObserver struct{ events []opEvent }
+
+func (o *recObserver) ObserveOp(op iox.Op, n int, err error) {
+	o.events = append(o.events, opEvent{op, n, err})
+}
+
+func TestCopyWith_Observer_SlowPathSequence(t *testing.T) {
+	obs := &recObserver{}
+	opts := iox.CopyOpts{Observer: obs}
+	src := &workingSeeker{data: []byte("hello")}
+	dst := &wouldBlockOnceWriter{}
+	n, err := iox.CopyWith(dst, src, opts)
+	if !errors.Is(err, iox.ErrWouldBlock) || n != 0 {
+		t.Fatalf("first: want (0, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	n, err = iox.CopyWith(dst, src, opts)
+	if err != nil || n != 5 || dst.buf.String() != "hello" {
+		t.Fatalf("second: n=%d err=%v dst=%q", n, err, dst.buf.String())
+	}
+	want := []opEvent{
+		{iox.OpCopyRead, 5, nil},
+		{iox.OpCopyWrite, 0, iox.ErrWouldBlock},
+		{iox.OpCopyRead, 5, nil},
+		{iox.OpCopyWrite, 5, nil},
+		{iox.OpCopyRead, 0, iox.EOF},
+	}
+	if len(obs.events) != len(want) {
+		t.Fatalf("events=%v", obs.events)
+	}
+	for i := range want {
+		if obs.events[i] != want[i] {
+			t.Fatalf("event %d = %v, want %v", i, obs.events[i], want[i])
+		}
+	}
+}
+
+func TestCopyWith_Observer_FastPaths(t *testing.T) {
+	obs := &recObserver{}
+	var dst bytes.Buffer
+	if _, err := iox.CopyWith(&dst, wtReader{n: 3, err: iox.ErrMore}, iox.CopyOpts{Observer: obs}); !errors.Is(err, iox.ErrMore) {
+		t.Fatalf("want ErrMore got %v", err)
+	}
+	if _, err := iox.CopyWith(rfWriter{n: 2, err: iox.EOF}, &plainReader{}, iox.CopyOpts{Observer: obs}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	want := []opEvent{{iox.OpCopyWriterTo, 3, iox.ErrMore}, {iox.OpCopyReaderFrom, 2, iox.EOF}}
+	if len(obs.events) != 2 || obs.events[0] != want[0] || obs.events[1] != want[1] {
+		t.Fatalf("events=%v want %v", obs.events, want)
+	}
+}
+
+func TestCopyWithPolicy_Observer(t *testing.T) {
+	obs := &recObserver{}
+	opts := iox.CopyOpts{Observer: obs}
+	dst := &wouldBlockOnceWriter{}
+	n, err := iox.CopyWithPolicy(dst, &plainReader{data: []byte("hello")}, iox.YieldPolicy{}, opts)
+	if err != nil || n != 5 || dst.buf.String() != "hello" {
+		t.Fatalf("loop: n=%d err=%v dst=%q", n, err, dst.buf.String())
+	}
+	want := []opEvent{
+		{iox.OpCopyRead, 5, nil},
+		{iox.OpCopyWrite, 0, iox.ErrWouldBlock},
+		{iox.OpCopyWrite, 5, nil},
+		{iox.OpCopyRead, 0, iox.EOF},
+	}
+	if fmt.Sprint(obs.events) != fmt.Sprint(want) {
+		t.Fatalf("loop events=%v want %v", obs.events, want)
+	}
+
+	// Each retried fast-path attempt is observed.
+	obs.events = nil
+	var buf bytes.Buffer
+	if n, err = iox.CopyWithPolicy(&buf, &wtWBOnce{data: []byte("wt")}, iox.YieldPolicy{}, opts); err != nil || n != 2 {
+		t.Fatalf("fast path: n=%d err=%v", n, err)
+	}
+	want = []opEvent{{iox.OpCopyWriterTo, 0, iox.ErrWouldBlock}, {iox.OpCopyWriterTo, 2, nil}}
+	if fmt.Sprint(obs.events) != fmt.Sprint(want) {
+		t.Fatalf("fast path events=%v want %v", obs.events, want)
+	}
+}
+
+// greedyWT is a seekable WriterTo that consumes everything remaining on each
+// WriteTo, even when w accepts less; CopyN must seek it back.
+type greedyWT struct{ workingSeeker }
+
+func (g *greedyWT) WriteTo(w iox.Writer) (int64, error) {
+	chunk := g.data[g.pos:]
+	g.pos = len(g.data)
+	n, err := w.Write(chunk)
+	return int64(n), err
+}
+
+func TestCopyN_WriterToFastPath(t *testing.T) {
+	data := []byte("0123456789abcdef")
+	for _, n := range []int64{1, 7, 16} {
+		var fast, slow bytes.Buffer
+		fsrc := bytes.NewReader(data)
+		wf, ef := iox.CopyN(&fast, fsrc, n)
+		ws, es := iox.CopyN(&slow, noWTReader{bytes.NewReader(data)}, n)
+		if wf != ws || ef != es || !bytes.Equal(fast.Bytes(), slow.Bytes()) {
+			t.Fatalf("n=%d: fast=(%d,%v,%q) slow=(%d,%v,%q)", n, wf, ef, fast.Bytes(), ws, es, slow.Bytes())
+		}
+		if fsrc.Len() != len(data)-int(n) {
+			t.Fatalf("n=%d: source over-read, %d bytes left", n, fsrc.Len())
+		}
+	}
+
+	var buf bytes.Buffer
+	w, err := iox.CopyN(&buf, bytes.NewReader(data[:5]), 8)
+	if w != 5 || err != io.ErrUnexpectedEOF || buf.String() != "01234" {
+		t.Fatalf("short src: w=%d err=%v buf=%q", w, err, buf.String())
+	}
+
+	g := &greedyWT{workingSeeker{data: data}}
+	buf.Reset()
+	w, err = iox.CopyNBuffer(&buf, g, 4, nil)
+	if w != 4 || err != nil || buf.String() != "0123" {
+		t.Fatalf("greedy: w=%d err=%v buf=%q", w, err, buf.String())
+	}
+	rest, _ := io.ReadAll(&g.workingSeeker)
+	if string(rest) != "456789abcdef" {
+		t.Fatalf("greedy: rest=%q", rest)
+	}
+}
+
+// chunkReader returns each chunk with a nil error, then EOF.
+type chunkReader struct {
+	chunks [][]byte
+	i      int
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if r.i >= len(r.chunks) {
+		return 0, iox.EOF
+	}
+	n := copy(p, r.chunks[r.i])
+	r.i++
+	return n, nil
+}
+
+type writeAtCall struct {
+	off  int64
+	data string
+}
+
+// recWriterAt records every WriteAt call; limit > 0 caps bytes per call and
+// err, if set, is returned with a capped write.
+type recWriterAt struct {
+	calls []writeAtCall
+	limit int
+	err   error
+}
+
+func (w *recWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	if w.limit > 0 && len(p) > w.limit {
+		p = p[:w.limit]
+		w.calls = append(w.calls, writeAtCall{off, string(p)})
+		return len(p), w.err
+	}
+	w.calls = append(w.calls, writeAtCall{off, string(p)})
+	return len(p), nil
+}
+
+func TestCopyAt_ContiguousOffsets(t *testing.T) {
+	src := &chunkReader{chunks: [][]byte{[]byte("abc"), []byte("de"), []byte("fghi")}}
+	dst := &recWriterAt{}
+	n, err := iox.CopyAt(dst, src, 100)
+	if n != 9 || err != nil {
+		t.Fatalf("CopyAt=(%d,%v)", n, err)
+	}
+	want := []writeAtCall{{100, "abc"}, {103, "de"}, {105, "fghi"}}
+	if len(dst.calls) != len(want) {
+		t.Fatalf("calls=%v", dst.calls)
+	}
+	for i := range want {
+		if dst.calls[i] != want[i] {
+			t.Fatalf("call %d=%v want %v", i, dst.calls[i], want[i])
+		}
+	}
+}
+
+func TestCopyAt_SemanticsAndShortWrite(t *testing.T) {
+	src := &moreChunksReader{chunks: [][]byte{[]byte("ab"), []byte("cd")}}
+	dst := &recWriterAt{}
+	n, err := iox.CopyAt(dst, src, 10)
+	if n != 2 || err != iox.ErrMore {
+		t.Fatalf("first=(%d,%v) want (2, ErrMore)", n, err)
+	}
+	n, err = iox.CopyAt(dst, src, 10+n)
+	if n != 2 || err != nil || dst.calls[1] != (writeAtCall{12, "cd"}) {
+		t.Fatalf("resume=(%d,%v) calls=%v", n, err, dst.calls)
+	}
+
+	n, err = iox.CopyAt(&recWriterAt{}, errReaderAlwaysWB{}, 0)
+	if n != 0 || err != iox.ErrWouldBlock {
+		t.Fatalf("would-block=(%d,%v)", n, err)
+	}
+
+	short := &recWriterAt{limit: 2}
+	n, err = iox.CopyAt(short, &chunkReader{chunks: [][]byte{[]byte("wxyz")}}, 0)
+	if n != 2 || err != io.ErrShortWrite {
+		t.Fatalf("short=(%d,%v) want (2, ErrShortWrite)", n, err)
+	}
+}
+
+func TestCopyAt_PartialWriteRollback(t *testing.T) {
+	src := bytes.NewReader([]byte("abcdef"))
+	dst := &recWriterAt{limit: 2, err: iox.ErrWouldBlock}
+	n, err := iox.CopyAt(dst, src, 0)
+	if n != 2 || err != iox.ErrWouldBlock {
+		t.Fatalf("partial=(%d,%v) want (2, ErrWouldBlock)", n, err)
+	}
+	dst.limit = 0
+	n, err = iox.CopyAt(dst, src, 2)
+	if n != 4 || err != nil || dst.calls[1] != (writeAtCall{2, "cdef"}) {
+		t.Fatalf("resume=(%d,%v) calls=%v", n, err, dst.calls)
+	}
+
+	n, err = iox.CopyAt(&recWriterAt{limit: 2, err: iox.ErrMore}, &plainReader{data: []byte("abcdef")}, 0)
+	if n != 2 || err != iox.ErrNoSeeker {
+		t.Fatalf("non-seekable=(%d,%v) want (2, ErrNoSeeker)", n, err)
+	}
+
+	if _, err = iox.CopyAt(nil, src, 0); err != iox.ErrNilArgument {
+		t.Fatalf("nil dst: %v", err)
+	}
+	if _, err = iox.CopyAt(dst, nil, 0); err != iox.ErrNilArgument {
+		t.Fatalf("nil src: %v", err)
+	}
+}
+
+func TestCopyFromAt(t *testing.T) {
+	src := bytes.NewReader([]byte("0123456789"))
+	var dst bytes.Buffer
+	n, err := iox.CopyFromAt(&dst, src, 3, 4)
+	if n != 4 || err != nil || dst.String() != "3456" {
+		t.Fatalf("exact: (%d,%v,%q)", n, err, dst.String())
+	}
+
+	dst.Reset()
+	n, err = iox.CopyFromAt(&dst, src, 7, 5)
+	if n != 3 || err != io.ErrUnexpectedEOF || dst.String() != "789" {
+		t.Fatalf("short: (%d,%v,%q)", n, err, dst.String())
+	}
+
+	wb := &wbOnceReaderAt{data: []byte("abcdef")}
+	dst.Reset()
+	n, err = iox.CopyFromAt(&dst, wb, 1, 4)
+	if n != 0 || err != iox.ErrWouldBlock {
+		t.Fatalf("would-block: (%d,%v)", n, err)
+	}
+	n, err = iox.CopyFromAt(&dst, wb, 1+n, 4-n)
+	if n != 4 || err != nil || dst.String() != "bcde" {
+		t.Fatalf("resume: (%d,%v,%q)", n, err, dst.String())
+	}
+
+	if n, err := iox.CopyFromAt(&dst, src, 0, 0); n != 0 || err != nil {
+		t.Fatalf("n=0: (%d,%v)", n, err)
+	}
+}
+
+// wbTwiceReader would-blocks twice, then delivers data through EOF.
+type wbTwiceReader struct {
+	blocks int
+	data   []byte
+}
+
+func (r *wbTwiceReader) Read(p []byte) (int, error) {
+	if r.blocks < 2 {
+		r.blocks++
+		return 0, iox.ErrWouldBlock
+	}
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestDrain(t *testing.T) {
+	r := &wbTwiceReader{data: bytes.Repeat([]byte("z"), 40000)}
+	n, err := iox.Drain(r, iox.YieldPolicy{})
+	if n != 40000 || err != nil {
+		t.Fatalf("YieldPolicy: (%d,%v) want (40000,nil)", n, err)
+	}
+
+	r = &wbTwiceReader{data: []byte("abc")}
+	n, err = iox.Drain(r, iox.ReturnPolicy{})
+	if n != 0 || err != iox.ErrWouldBlock {
+		t.Fatalf("ReturnPolicy: (%d,%v) want (0,ErrWouldBlock)", n, err)
+	}
+	src := &dataThenAlwaysWBReader{data: []byte("abcd")}
+	n, err = iox.Drain(src, nil)
+	if n != 4 || err != iox.ErrWouldBlock {
+		t.Fatalf("nil policy: (%d,%v) want (4,ErrWouldBlock)", n, err)
+	}
+}
+
+// multiRF is a multi-shot ReaderFrom: each ReadFrom consumes one chunk from r
+// and returns ErrMore until r is exhausted.
+type multiRF struct {
+	buf   bytes.Buffer
+	calls int
+}
+
+func (w *multiRF) Write(p []byte) (int, error) { return w.buf.Write(p) }
+
+func (w *multiRF) ReadFrom(r iox.Reader) (int64, error) {
+	w.calls++
+	p := make([]byte, 3)
+	n, err := r.Read(p)
+	w.buf.Write(p[:n])
+	if err != nil {
+		return int64(n), err
+	}
+	return int64(n), iox.ErrMore
+}
+
+func (*multiRF) MultiShot() {}
+
+func TestCopyBuffer_ReaderFromMulti(t *testing.T) {
+	dst := &multiRF{}
+	n, err := iox.CopyBuffer(dst, &plainReader{data: []byte("abcdefgh")}, nil)
+	if n != 8 || err != nil || dst.buf.String() != "abcdefgh" {
+		t.Fatalf("CopyBuffer=(%d,%v,%q)", n, err, dst.buf.String())
+	}
+	// Three chunks with ErrMore, then a (0, EOF) call ends the loop.
+	if dst.calls != 4 {
+		t.Fatalf("ReadFrom calls=%d want 4", dst.calls)
+	}
+
+	// Plain Copy still surfaces the first ErrMore.
+	dst = &multiRF{}
+	n, err = iox.Copy(dst, &plainReader{data: []byte("abcdefgh")})
+	if n != 3 || err != iox.ErrMore || dst.calls != 1 {
+		t.Fatalf("Copy=(%d,%v) calls=%d", n, err, dst.calls)
+	}
+
+	// Would-block from the source stops the loop with progress so far.
+	dst = &multiRF{}
+	n, err = iox.CopyBuffer(dst, &dataThenAlwaysWBReader{data: []byte("xyz")}, nil)
+	if n != 3 || err != iox.ErrWouldBlock {
+		t.Fatalf("would-block=(%d,%v)", n, err)
+	}
+}
+
+// stuckReader returns (0, nil) forever, except every data-th call (if data>0)
+// delivers one byte.
+type stuckReader struct {
+	data  int
+	calls int
+}
+
+func (r *stuckReader) Read(p []byte) (int, error) {
+	r.calls++
+	if r.data > 0 && r.calls%r.data == 0 {
+		return copy(p, "x"), nil
+	}
+	return 0, nil
+}
+
+func TestProgressGuard(t *testing.T) {
+	const max = 3
+	r := iox.ProgressGuard(&stuckReader{}, max)
+	var b [4]byte
+	for i := 0; i < max; i++ {
+		if n, err := r.Read(b[:]); n != 0 || err != nil {
+			t.Fatalf("read %d=(%d,%v) want (0,nil)", i, n, err)
+		}
+	}
+	if n, err := r.Read(b[:]); n != 0 || err != io.ErrNoProgress {
+		t.Fatalf("read %d=(%d,%v) want (0,ErrNoProgress)", max, n, err)
+	}
+
+	// Interleaved data resets the count: max empty reads, then one byte.
+	r = iox.ProgressGuard(&stuckReader{data: max + 1}, max)
+	for i := 0; i < 4*(max+1); i++ {
+		if _, err := r.Read(b[:]); err != nil {
+			t.Fatalf("read %d: unexpected %v with interleaved data", i, err)
+		}
+	}
+
+	// maxNoProgress 0 rejects the first (0, nil); empty p is not counted.
+	r = iox.ProgressGuard(&stuckReader{}, 0)
+	if n, err := r.Read(nil); n != 0 || err != nil {
+		t.Fatalf("empty p=(%d,%v) want (0,nil)", n, err)
+	}
+	if _, err := r.Read(b[:]); err != io.ErrNoProgress {
+		t.Fatalf("max=0: err=%v want ErrNoProgress", err)
+	}
+}
+
+func TestCopyNMore(t *testing.T) {
+	src := &moreChunksReader{chunks: [][]byte{[]byte("ab"), []byte("cd"), []byte("ef"), []byte("gh")}}
+	var dst bytes.Buffer
+	n, err := iox.CopyNMore(&dst, src, 6, iox.YieldPolicy{})
+	if n != 6 || err != nil || dst.String() != "abcdef" {
+		t.Fatalf("exact: (%d,%v,%q)", n, err, dst.String())
+	}
+
+	dst.Reset()
+	src = &moreChunksReader{chunks: [][]byte{[]byte("ab"), []byte("cd")}}
+	n, err = iox.CopyNMore(&dst, src, 6, iox.YieldPolicy{})
+	if n != 4 || err != io.ErrUnexpectedEOF {
+		t.Fatalf("short: (%d,%v) want (4,ErrUnexpectedEOF)", n, err)
+	}
+
+	// Would-block stays a policy decision; nil policy returns.
+	n, err = iox.CopyNMore(&dst, errReaderAlwaysWB{}, 3, nil)
+	if n != 0 || err != iox.ErrWouldBlock {
+		t.Fatalf("would-block: (%d,%v)", n, err)
+	}
+	if n, err = iox.CopyNMore(&dst, src, 0, nil); n != 0 || err != nil {
+		t.Fatalf("n=0: (%d,%v)", n, err)
+	}
+}
+
+func TestCopyAsync(t *testing.T) {
+	var dst bytes.Buffer
+	ch := iox.CopyAsync(&dst, bytes.NewReader([]byte("async")))
+	res, ok := <-ch
+	if !ok || res.Written != 5 || res.Err != nil || dst.String() != "async" {
+		t.Fatalf("success: ok=%v res=%+v dst=%q", ok, res, dst.String())
+	}
+	if _, ok := <-ch; ok {
+		t.Fatal("channel not closed after one result")
+	}
+
+	src := &moreChunksReader{chunks: [][]byte{[]byte("ab"), []byte("cd")}}
+	res = <-iox.CopyAsync(io.Discard, src)
+	if res.Written != 2 || res.Err != iox.ErrMore || res.Outcome != iox.OutcomeMore {
+		t.Fatalf("more: res=%+v want {2 ErrMore}", res)
+	}
+}
+
+func TestCopyAllFrom(t *testing.T) {
+	var dst bytes.Buffer
+	n, err := iox.CopyAllFrom(&dst,
+		bytes.NewReader([]byte("part1-")),
+		&plainReader{data: []byte("part2-")},
+		bytes.NewReader([]byte("part3")))
+	if n != 17 || err != nil || dst.String() != "part1-part2-part3" {
+		t.Fatalf("concat: (%d,%v,%q)", n, err, dst.String())
+	}
+
+	dst.Reset()
+	third := &plainReader{data: []byte("never")}
+	n, err = iox.CopyAllFrom(&dst,
+		bytes.NewReader([]byte("a")),
+		&dataThenAlwaysWBReader{data: []byte("bc")},
+		third)
+	if n != 3 || !iox.IsWouldBlock(err) || dst.String() != "abc" {
+		t.Fatalf("would-block: (%d,%v,%q)", n, err, dst.String())
+	}
+	var se *iox.SourceError
+	if !errors.As(err, &se) || se.Index != 1 || se.Err != iox.ErrWouldBlock {
+		t.Fatalf("SourceError=%+v", se)
+	}
+	if third.pos != 0 {
+		t.Fatal("third source was read")
+	}
+	if got := err.Error(); got != "source 1: io: would block" {
+		t.Fatalf("Error()=%q", got)
+	}
+}
+
+// heldReader signals started on its first Read, then blocks until release
+// is closed and reports EOF.
+type heldReader struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (r *heldReader) Read(p []byte) (int, error) {
+	close(r.started)
+	<-r.release
+	return 0, io.EOF
+}
+
+func TestCopyBufferWith_DetectConcurrentBuffer(t *testing.T) {
+	buf := make([]byte, 64)
+	opts := iox.CopyOpts{DetectConcurrentBuffer: true}
+	held := &heldReader{started: make(chan struct{}), release: make(chan struct{})}
+	done := make(chan error, 1)
+	go func() {
+		_, err := iox.CopyBufferWith(&sliceWriter{}, held, buf, opts)
+		done <- err
+	}()
+	<-held.started
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatal("expected panic for a buffer shared by concurrent copies")
+			}
+		}()
+		_, _ = iox.CopyBufferWith(&sliceWriter{}, &plainReader{data: []byte("x")}, buf[:8], opts)
+	}()
+
+	close(held.release)
+	if err := <-done; err != nil {
+		t.Fatalf("first copy: %v", err)
+	}
+	// Released on return: the buffer can be used again.
+	var dst sliceWriter
+	if n, err := iox.CopyBufferWith(&dst, &plainReader{data: []byte("again")}, buf, opts); n != 5 || err != nil {
+		t.Fatalf("reuse: n=%d err=%v", n, err)
+	}
+}
+
+func TestCopyBufferInfo(t *testing.T) {
+	var dst sliceWriter
+	n, info, err := iox.CopyBufferInfo(&dst, bytes.NewReader([]byte("wt")), nil)
+	if n != 2 || err != nil || info != (iox.BufferInfo{UsedFastPath: true}) {
+		t.Fatalf("WriterTo: n=%d err=%v info=%+v", n, err, info)
+	}
+
+	var rf bytes.Buffer
+	n, info, err = iox.CopyBufferInfo(&rf, &plainReader{data: []byte("rf")}, make([]byte, 8))
+	if n != 2 || err != nil || info != (iox.BufferInfo{UsedFastPath: true}) {
+		t.Fatalf("ReaderFrom: n=%d err=%v info=%+v", n, err, info)
+	}
+
+	n, info, err = iox.CopyBufferInfo(&dst, &plainReader{data: []byte("slow")}, make([]byte, 3))
+	if n != 4 || err != nil || info != (iox.BufferInfo{BufferSize: 3}) {
+		t.Fatalf("slow: n=%d err=%v info=%+v", n, err, info)
+	}
+	_, info, _ = iox.CopyBufferInfo(&dst, &plainReader{data: []byte("x")}, nil)
+	if info.UsedFastPath || info.BufferSize != len(iox.Buffer{}) {
+		t.Fatalf("nil buf: info=%+v", info)
+	}
+}
+
+func TestCopyPolicySize(t *testing.T) {
+	src := &countingReader{r: &plainReader{data: []byte("abcdefghij")}}
+	var dst sliceWriter
+	n, err := iox.CopyPolicySize(&dst, src, 3, iox.YieldPolicy{})
+	if n != 10 || err != nil || string(dst.data) != "abcdefghij" {
+		t.Fatalf("(%d,%v,%q)", n, err, dst.data)
+	}
+	// 4 reads of <=3 bytes, then EOF.
+	if src.calls != 5 {
+		t.Fatalf("reads=%d want 5", src.calls)
+	}
+
+	// Larger than the pooled Buffer is allocated.
+	dst.data = nil
+	n, err = iox.CopyPolicySize(&dst, &plainReader{data: []byte("big")}, 2*len(iox.Buffer{}), nil)
+	if n != 3 || err != nil {
+		t.Fatalf("large: (%d,%v)", n, err)
+	}
+
+	for _, size := range []int{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("bufSize=%d: no panic", size)
+				}
+			}()
+			_, _ = iox.CopyPolicySize(&dst, &plainReader{}, size, nil)
+		}()
+	}
+}
+
+// everyKMoreWriter accepts bytes but stops with ErrMore each time its running
+// total reaches a multiple of k.
+type everyKMoreWriter struct {
+	k     int
+	total int
+	buf   []byte
+}
+
+func (w *everyKMoreWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if rem := w.k - w.total%w.k; n >= rem {
+		n = rem
+	}
+	w.buf = append(w.buf, p[:n]...)
+	w.total += n
+	if w.total%w.k == 0 {
+		return n, iox.ErrMore
+	}
+	return n, nil
+}
+
+func TestCopyFrames(t *testing.T) {
+	dst := &everyKMoreWriter{k: 4}
+	src := &chunkReader{chunks: [][]byte{[]byte("abcdef"), []byte("ghijk")}}
+	var frames []int64
+	n, err := iox.CopyFrames(dst, src, func(f int64) { frames = append(frames, f) })
+	if n != 11 || err != nil || string(dst.buf) != "abcdefghijk" {
+		t.Fatalf("(%d,%v,%q)", n, err, dst.buf)
+	}
+	// Boundaries at 4 and 8; the trailing 3 bytes are not a frame.
+	if len(frames) != 2 || frames[0] != 4 || frames[1] != 4 {
+		t.Fatalf("frames=%v want [4 4]", frames)
+	}
+
+	// Reader-side semantics are still returned.
+	frames = nil
+	n, err = iox.CopyFrames(&everyKMoreWriter{k: 2}, &dataThenAlwaysWBReader{data: []byte("xyz")}, func(f int64) { frames = append(frames, f) })
+	if n != 3 || err != iox.ErrWouldBlock || len(frames) != 1 || frames[0] != 2 {
+		t.Fatalf("would-block: (%d,%v) frames=%v", n, err, frames)
+	}
+}
+
+func TestFrameReader(t *testing.T) {
+	data := []byte("aaaabbbbcc")
+
+	var dst sliceWriter
+	n, err := iox.CopyPolicy(&dst, iox.FrameReader(&plainReader{data: data}, 4), iox.YieldPolicy{})
+	if n != 4 || err != iox.ErrMore || string(dst.data) != "aaaa" {
+		t.Fatalf("YieldPolicy: (%d,%v,%q)", n, err, dst.data)
+	}
+
+	dst.data = nil
+	retry := &iox.PolicyFunc{
+		YieldFunc: func(iox.Op) {},
+		MoreFunc:  func(iox.Op) iox.PolicyAction { return iox.PolicyRetry },
+	}
+	n, err = iox.CopyPolicy(&dst, iox.FrameReader(&plainReader{data: data}, 4), retry)
+	if n != 10 || err != nil || string(dst.data) != string(data) {
+		t.Fatalf("retry: (%d,%v,%q)", n, err, dst.data)
+	}
+
+	// Reads never cross a boundary; the short last frame ends with EOF.
+	fr := iox.FrameReader(&plainReader{data: data}, 4)
+	p := make([]byte, 6)
+	var got []string
+	for {
+		n, err := fr.Read(p)
+		got = append(got, fmt.Sprintf("%s/%v", p[:n], err))
+		if err == io.EOF {
+			break
+		}
+	}
+	want := "[aaaa/io: expect more bbbb/io: expect more cc/<nil> /EOF]"
+	if fmt.Sprint(got) != want {
+		t.Fatalf("reads=%v want %s", got, want)
+	}
+}
+
+func TestCopyNResume(t *testing.T) {
+	var dst sliceWriter
+	w, rem, err := iox.CopyNResume(&dst, &plainReader{data: []byte("abcdef")}, 6)
+	if w != 6 || rem != 0 || err != nil {
+		t.Fatalf("exact: (%d,%d,%v)", w, rem, err)
+	}
+
+	dst.data = nil
+	src := &dataThenAlwaysWBReader{data: []byte("abc")}
+	w, rem, err = iox.CopyNResume(&dst, src, 8)
+	if w != 3 || rem != 5 || err != iox.ErrWouldBlock {
+		t.Fatalf("would-block: (%d,%d,%v) want (3,5,ErrWouldBlock)", w, rem, err)
+	}
+
+	w, rem, err = iox.CopyNResume(&dst, &plainReader{data: []byte("xy")}, 5)
+	if w != 2 || rem != 3 || err != io.ErrUnexpectedEOF {
+		t.Fatalf("short: (%d,%d,%v) want (2,3,ErrUnexpectedEOF)", w, rem, err)
+	}
+	if w, rem, err = iox.CopyNResume(&dst, &plainReader{}, 0); w != 0 || rem != 0 || err != nil {
+		t.Fatalf("n=0: (%d,%d,%v)", w, rem, err)
+	}
+}
+
+func TestCopyNNotify(t *testing.T) {
+	calls := 0
+	done := func() { calls++ }
+
+	var dst bytes.Buffer
+	n, err := iox.CopyNNotify(&dst, bytes.NewReader([]byte("hello world")), 5, done)
+	if err != nil || n != 5 || calls != 1 || dst.String() != "hello" {
+		t.Fatalf("exact: n=%d err=%v calls=%d dst=%q", n, err, calls, dst.String())
+	}
+
+	calls = 0
+	n, err = iox.CopyNNotify(&sliceWriter{}, bytes.NewReader([]byte("abc")), 5, done)
+	if !errors.Is(err, io.ErrUnexpectedEOF) || n != 3 || calls != 0 {
+		t.Fatalf("short: n=%d err=%v calls=%d", n, err, calls)
+	}
+
+	n, err = iox.CopyNNotify(&sliceWriter{}, errReaderAlwaysWB{}, 5, done)
+	if !errors.Is(err, iox.ErrWouldBlock) || n != 0 || calls != 0 {
+		t.Fatalf("would block: n=%d err=%v calls=%d", n, err, calls)
+	}
+
+	if _, err = iox.CopyNNotify(&sliceWriter{}, bytes.NewReader([]byte("x")), 1, nil); err != nil {
+		t.Fatalf("nil callback: %v", err)
+	}
+}
+
+func TestCopy_NilArgument(t *testing.T) {
+	var dst bytes.Buffer
+	src := bytes.NewReader([]byte("abc"))
+	cases := []struct {
+		name string
+		dst  iox.Writer
+		src  iox.Reader
+	}{
+		{"nil dst", nil, src},
+		{"nil src", &dst, nil},
+		{"both nil", nil, nil},
+	}
+	for _, tc := range cases {
+		if n, err := iox.Copy(tc.dst, tc.src); n != 0 || err != iox.ErrNilArgument {
+			t.Errorf("%s: Copy=(%d,%v) want (0,ErrNilArgument)", tc.name, n, err)
+		}
+		if n, err := iox.CopyBuffer(tc.dst, tc.src, make([]byte, 8)); n != 0 || err != iox.ErrNilArgument {
+			t.Errorf("%s: CopyBuffer=(%d,%v) want (0,ErrNilArgument)", tc.name, n, err)
+		}
+		if n, err := iox.CopyPolicy(tc.dst, tc.src, iox.YieldPolicy{}); n != 0 || err != iox.ErrNilArgument {
+			t.Errorf("%s: CopyPolicy=(%d,%v) want (0,ErrNilArgument)", tc.name, n, err)
+		}
+		if n, err := iox.CopyN(tc.dst, tc.src, 2); n != 0 || err != iox.ErrNilArgument {
+			t.Errorf("%s: CopyN=(%d,%v) want (0,ErrNilArgument)", tc.name, n, err)
+		}
+		if n, err := iox.CopyWriteN(tc.dst, tc.src, 2); n != 0 || err != iox.ErrNilArgument {
+			t.Errorf("%s: CopyWriteN=(%d,%v) want (0,ErrNilArgument)", tc.name, n, err)
+		}
+	}
+	if src.Len() != 3 || dst.Len() != 0 {
+		t.Fatalf("I/O performed: src.Len()=%d dst.Len()=%d", src.Len(), dst.Len())
+	}
+
+	// The ReaderFromMulti fast path must not run before the nil check.
+	if n, err := iox.CopyBuffer(&multiRF{}, nil, nil); n != 0 || err != iox.ErrNilArgument {
+		t.Errorf("ReaderFromMulti dst: CopyBuffer=(%d,%v) want (0,ErrNilArgument)", n, err)
+	}
+	if n, err := iox.CopyFromAt(&dst, nil, 0, 2); n != 0 || err != iox.ErrNilArgument {
+		t.Errorf("nil src: CopyFromAt=(%d,%v) want (0,ErrNilArgument)", n, err)
+	}
+	if n, err := iox.CopyFromAt(nil, src, 0, 2); n != 0 || err != iox.ErrNilArgument {
+		t.Errorf("nil dst: CopyFromAt=(%d,%v) want (0,ErrNilArgument)", n, err)
+	}
+}
+
+// pathSrc implements both Reader and WriterTo, recording the path taken.
+type pathSrc struct {
+	data []byte
+	path *[]string
+}
+
+func (s *pathSrc) Read(p []byte) (int, error) {
+	*s.path = append(*s.path, "Read")
+	if len(s.data) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, s.data)
+	s.data = s.data[n:]
+	return n, nil
+}
+
+func (s *pathSrc) WriteTo(w io.Writer) (int64, error) {
+	*s.path = append(*s.path, "WriteTo")
+	n, err := w.Write(s.data)
+	s.data = s.data[n:]
+	return int64(n), err
+}
+
+// pathDst implements both Writer and ReaderFrom, recording the path taken.
+type pathDst struct {
+	buf  bytes.Buffer
+	path *[]string
+}
+
+func (d *pathDst) Write(p []byte) (int, error) { return d.buf.Write(p) }
+
+func (d *pathDst) ReadFrom(r io.Reader) (int64, error) {
+	*d.path = append(*d.path, "ReadFrom")
+	return d.buf.ReadFrom(r)
+}
+
+func TestCopyOpts_PreferReaderFrom(t *testing.T) {
+	cases := []struct {
+		prefer bool
+		policy iox.SemanticPolicy
+		first  string
+	}{
+		{false, nil, "WriteTo"},
+		{true, nil, "ReadFrom"},
+		{false, iox.YieldPolicy{}, "WriteTo"},
+		{true, iox.YieldPolicy{}, "ReadFrom"},
+	}
+	for _, tc := range cases {
+		var path []string
+		src := &pathSrc{data: []byte("hello"), path: &path}
+		dst := &pathDst{path: &path}
+		var n int64
+		var err error
+		if tc.policy == nil {
+			n, err = iox.CopyWith(dst, src, iox.CopyOpts{PreferReaderFrom: tc.prefer})
+		} else {
+			n, err = iox.CopyWithPolicy(dst, src, tc.policy, iox.CopyOpts{PreferReaderFrom: tc.prefer})
+		}
+		if n != 5 || err != nil || dst.buf.String() != "hello" {
+			t.Fatalf("prefer=%v policy=%v: n=%d err=%v dst=%q", tc.prefer, tc.policy, n, err, dst.buf.String())
+		}
+		if len(path) == 0 || path[0] != tc.first {
+			t.Fatalf("prefer=%v policy=%v: path=%v want first %s", tc.prefer, tc.policy, path, tc.first)
+		}
+	}
+}
+
+// flushRecorder is a Writer with Flush that logs writes and flushes.
+type flushRecorder struct {
+	log      []string
+	flushErr error
+}
+
+func (f *flushRecorder) Write(p []byte) (int, error) {
+	f.log = append(f.log, "write:"+string(p))
+	return len(p), nil
+}
+
+func (f *flushRecorder) Flush() error {
+	f.log = append(f.log, "flush")
+	return f.flushErr
+}
+
+func TestCopyFlushing(t *testing.T) {
+	dst := &flushRecorder{}
+	src := &moreChunksReader{chunks: [][]byte{[]byte("rec1"), []byte("rec2")}}
+	n, err := iox.CopyFlushing(dst, src)
+	if n != 4 || err != iox.ErrMore {
+		t.Fatalf("first: n=%d err=%v want (4,ErrMore)", n, err)
+	}
+	if got := fmt.Sprint(dst.log); got != "[write:rec1 flush]" {
+		t.Fatalf("log=%s want flush after the boundary", got)
+	}
+
+	// EOF completion does not flush.
+	n, err = iox.CopyFlushing(dst, src)
+	if n != 4 || err != nil || fmt.Sprint(dst.log) != "[write:rec1 flush write:rec2]" {
+		t.Fatalf("eof: n=%d err=%v log=%v", n, err, dst.log)
+	}
+
+	// A failing Flush is reported.
+	boom := errors.New("flush failed")
+	dst = &flushRecorder{flushErr: boom}
+	src = &moreChunksReader{chunks: [][]byte{[]byte("x"), []byte("z")}}
+	if n, err := iox.CopyFlushing(dst, src); n != 1 || err != boom {
+		t.Fatalf("flush error: n=%d err=%v", n, err)
+	}
+
+	// Writers without Flush see plain Copy behavior.
+	var plain sliceWriter
+	src = &moreChunksReader{chunks: [][]byte{[]byte("y"), []byte("w")}}
+	if n, err := iox.CopyFlushing(&plain, src); n != 1 || err != iox.ErrMore {
+		t.Fatalf("no flusher: n=%d err=%v", n, err)
+	}
+}
+
+func TestCopyE(t *testing.T) {
+	var dst sliceWriter
+	res, err := iox.CopyE(&dst, &dataThenAlwaysWBReader{data: []byte("abc")})
+	if err != iox.ErrWouldBlock || res.Err != err || res.Written != 3 {
+		t.Fatalf("would-block: res=%+v err=%v", res, err)
+	}
+	if res.LastOp != iox.OpCopyRead || res.Outcome != iox.OutcomeWouldBlock {
+		t.Fatalf("would-block: LastOp=%v Outcome=%v want CopyRead/WouldBlock", res.LastOp, res.Outcome)
+	}
+
+	dst = sliceWriter{}
+	res, err = iox.CopyE(&dst, &plainReader{data: []byte("done")})
+	if err != nil || res.Written != 4 || res.LastOp != iox.OpCopyRead || res.Outcome != iox.OutcomeOK {
+		t.Fatalf("eof: res=%+v err=%v", res, err)
+	}
+
+	res, _ = iox.CopyE(&dst, bytes.NewReader([]byte("fast")))
+	if res.LastOp != iox.OpCopyWriterTo || res.Outcome != iox.OutcomeOK {
+		t.Fatalf("fast path: res=%+v", res)
+	}
+}
+
+func TestCopySplit(t *testing.T) {
+	src := iox.NewRewindReader(&plainReader{data: []byte("hello")})
+	dst := &gateWriter{quota: 2}
+	read, written, err := iox.CopySplit(dst, src)
+	if read != 5 || written != 2 || err != iox.ErrWouldBlock {
+		t.Fatalf("rollback: read=%d written=%d err=%v", read, written, err)
+	}
+
+	// The rolled-back bytes are read again on resume.
+	dst.quota = 100
+	read, written, err = iox.CopySplit(dst, src)
+	if read != 3 || written != 3 || err != nil || dst.buf.String() != "hello" {
+		t.Fatalf("resume: read=%d written=%d err=%v dst=%q", read, written, err, dst.buf.String())
+	}
+
+	read, written, err = iox.CopySplit(&sliceWriter{}, bytes.NewReader([]byte("fast")))
+	if read != 4 || written != 4 || err != nil {
+		t.Fatalf("fast path: read=%d written=%d err=%v", read, written, err)
+	}
+}
+
+func TestFastPathFor(t *testing.T) {
+	cases := []struct {
+		name   string
+		dst    iox.Writer
+		src    iox.Reader
+		wt, rf bool
+	}{
+		{"writer-to", &sliceWriter{}, bytes.NewReader(nil), true, false},
+		{"reader-from", &bytes.Buffer{}, &plainReader{}, false, true},
+		{"both", &bytes.Buffer{}, bytes.NewReader(nil), true, false},
+		{"neither", &sliceWriter{}, &plainReader{}, false, false},
+	}
+	for _, tc := range cases {
+		wt, rf := iox.FastPathFor(tc.dst, tc.src)
+		if wt != tc.wt || rf != tc.rf {
+			t.Fatalf("%s: got (%v,%v) want (%v,%v)", tc.name, wt, rf, tc.wt, tc.rf)
+		}
+	}
+}
+
+func TestCopyWhere(t *testing.T) {
+	var dst sliceWriter
+	n, op, err := iox.CopyWhere(&dst, &dataThenAlwaysWBReader{data: []byte("abc")})
+	if n != 3 || op != iox.OpCopyRead || err != iox.ErrWouldBlock {
+		t.Fatalf("read side=(%d,%v,%v) want (3,CopyRead,ErrWouldBlock)", n, op, err)
+	}
+
+	n, op, err = iox.CopyWhere(&gateWriter{quota: 2}, iox.NewRewindReader(&plainReader{data: []byte("abc")}))
+	if n != 2 || op != iox.OpCopyWrite || err != iox.ErrWouldBlock {
+		t.Fatalf("write side=(%d,%v,%v) want (2,CopyWrite,ErrWouldBlock)", n, op, err)
+	}
+
+	n, op, err = iox.CopyWhere(&gateWriter{quota: 2}, bytes.NewReader([]byte("abc")))
+	if n != 2 || op != iox.OpCopyWriterTo || err != iox.ErrWouldBlock {
+		t.Fatalf("fast path=(%d,%v,%v) want (2,CopyWriterTo,ErrWouldBlock)", n, op, err)
+	}
+
+	n, op, err = iox.CopyWhere(&dst, &plainReader{data: []byte("done")})
+	if n != 4 || op != iox.OpNone || err != nil {
+		t.Fatalf("clean=(%d,%v,%v) want (4,None,nil)", n, op, err)
+	}
+}
+
+func TestFramedWriter(t *testing.T) {
+	var sink sliceWriter
+	fw := iox.FramedWriter(&sink, 4)
+	p := []byte("aaaabbbbcc")
+	var got []string
+	for len(p) > 0 {
+		n, err := fw.Write(p)
+		got = append(got, fmt.Sprintf("%d/%v", n, err))
+		p = p[n:]
+	}
+	if want := "[4/io: expect more 4/io: expect more 2/<nil>]"; fmt.Sprint(got) != want {
+		t.Fatalf("writes=%v want %s", got, want)
+	}
+	// The partial frame continues across Writes.
+	if n, err := fw.Write([]byte("ccd")); n != 2 || err != iox.ErrMore {
+		t.Fatalf("completing write=(%d,%v) want (2,ErrMore)", n, err)
+	}
+	if string(sink.data) != "aaaabbbbcccc" {
+		t.Fatalf("sink=%q", sink.data)
+	}
+
+	// Copy surfaces each boundary and resumes from the seekable source.
+	sink.data = nil
+	fw = iox.FramedWriter(&sink, 3)
+	src := bytes.NewReader([]byte("abcdefg"))
+	var frames []int64
+	for {
+		n, err := iox.Copy(fw, src)
+		frames = append(frames, n)
+		if err != iox.ErrMore {
+			if err != nil {
+				t.Fatalf("copy err=%v", err)
+			}
+			break
+		}
+	}
+	if fmt.Sprint(frames) != "[3 3 1]" || string(sink.data) != "abcdefg" {
+		t.Fatalf("frames=%v sink=%q", frames, sink.data)
+	}
+}
+
+// panicWTReader adds a WriterTo that panics to any Reader.
+type panicWTReader struct{ iox.Reader }
+
+func (panicWTReader) WriteTo(iox.Writer) (int64, error) { panic("WriteTo must not be called") }
+
+func TestCopyWithPolicy_DisableFastPath(t *testing.T) {
+	// WriterTo is bypassed; the policy retries the read-side ErrWouldBlock.
+	var dst sliceWriter
+	src := panicWTReader{&wbTwiceReader{data: []byte("slow")}}
+	pol := &recPolicy{onWB: map[iox.Op]iox.PolicyAction{iox.OpCopyRead: iox.PolicyRetry}}
+	n, err := iox.CopyWithPolicy(&dst, src, pol, iox.CopyOpts{DisableFastPath: true})
+	if n != 4 || err != nil || string(dst.data) != "slow" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, dst.data)
+	}
+	if fmt.Sprint(pol.yields) != "[CopyRead CopyRead]" {
+		t.Fatalf("yields=%v want two CopyRead retries", pol.yields)
+	}
+
+	// ReaderFrom is bypassed as well.
+	rf := &panicRF{}
+	n, err = iox.CopyWithPolicy(rf, &plainReader{data: []byte("rf")}, iox.YieldPolicy{}, iox.CopyOpts{DisableFastPath: true})
+	if n != 2 || err != nil || string(rf.data) != "rf" {
+		t.Fatalf("ReaderFrom: n=%d err=%v dst=%q", n, err, rf.data)
+	}
+}
+
+func TestCopyNPolicyPooled(t *testing.T) {
+	var dst sliceWriter
+	src := &wbTwiceReader{data: []byte("abcdefgh")}
+	n, err := iox.CopyNPolicyPooled(&dst, src, 5, iox.YieldPolicy{})
+	if n != 5 || err != nil || string(dst.data) != "abcde" {
+		t.Fatalf("exact: n=%d err=%v dst=%q", n, err, dst.data)
+	}
+
+	dst.data = nil
+	n, err = iox.CopyNPolicyPooled(&dst, &plainReader{data: []byte("abc")}, 5, iox.YieldPolicy{})
+	if n != 3 || err != io.ErrUnexpectedEOF || string(dst.data) != "abc" {
+		t.Fatalf("short: n=%d err=%v dst=%q want (3,ErrUnexpectedEOF)", n, err, dst.data)
+	}
+}
+
+// offsetSink is an overwrite-safe sink: each write lands at the stream
+// offset it claims, so a restart from zero simply overwrites the prefix. It
+// would-blocks once, after accepting blockAt bytes of the first attempt.
+type offsetSink struct {
+	data    []byte
+	off     int
+	blockAt int
+	blocked bool
+}
+
+func (s *offsetSink) Write(p []byte) (int, error) {
+	n := len(p)
+	var err error
+	if !s.blocked && s.off+n > s.blockAt {
+		s.blocked = true
+		n, err = s.blockAt-s.off, iox.ErrWouldBlock
+	}
+	s.data = append(s.data[:s.off], p[:n]...)
+	s.off += n
+	return n, err
+}
+
+func TestCopyRestartable(t *testing.T) {
+	dst := &offsetSink{blockAt: 2}
+	opens := 0
+	src := func() iox.Reader {
+		opens++
+		dst.off = 0 // the restarted stream begins at offset 0 again
+		return &plainReader{data: []byte("abcdef")}
+	}
+	n, err := iox.CopyRestartable(dst, src, iox.ReturnPolicy{})
+	if n != 6 || err != nil || string(dst.data) != "abcdef" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, dst.data)
+	}
+	if opens != 2 {
+		t.Fatalf("opens=%d want 2 (one restart)", opens)
+	}
+
+	// Without the restart, the same sink reports ErrNoSeeker.
+	dst = &offsetSink{blockAt: 2}
+	if _, err := iox.Copy(dst, &plainReader{data: []byte("abcdef")}); err != iox.ErrNoSeeker {
+		t.Fatalf("Copy err=%v want ErrNoSeeker", err)
+	}
+}
+
+func TestCopyStd(t *testing.T) {
+	var dst sliceWriter
+	n, err := iox.CopyStd(&dst, &wbTwiceReader{data: []byte("blocking")})
+	if n != 8 || err != nil || string(dst.data) != "blocking" {
+		t.Fatalf("would-block twice: n=%d err=%v dst=%q", n, err, dst.data)
+	}
+
+	dst.data = nil
+	src := &moreChunksReader{chunks: [][]byte{[]byte("a"), []byte("b"), []byte("c")}}
+	n, err = iox.CopyStd(&dst, src)
+	if n != 3 || err != nil || string(dst.data) != "abc" {
+		t.Fatalf("more: n=%d err=%v dst=%q", n, err, dst.data)
+	}
+
+	boom := errors.New("boom")
+	if _, err := iox.CopyStd(&dst, errReader{err: boom}); err != boom {
+		t.Fatalf("failure: err=%v", err)
+	}
+}
+
+func TestChunkReader(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 10)
+	cr := iox.ChunkReader(bytes.NewReader(data), 7)
+	var got []byte
+	p := make([]byte, 64)
+	for {
+		n, err := cr.Read(p)
+		if n > 7 {
+			t.Fatalf("Read returned %d bytes, cap 7", n)
+		}
+		got = append(got, p[:n]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("err=%v", err)
+		}
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("data changed: %q", got)
+	}
+
+	// Semantics pass through.
+	cr = iox.ChunkReader(&moreChunksReader{chunks: [][]byte{[]byte("abcdef"), []byte("g")}}, 4)
+	if n, err := cr.Read(p); n != 4 || err != iox.ErrMore {
+		t.Fatalf("more=(%d,%v) want (4,ErrMore)", n, err)
+	}
+}
+
+func TestCopyVerify(t *testing.T) {
+	data := []byte("integrity matters")
+	sum := sha256.Sum256(data)
+	var dst bytes.Buffer
+	n, err := iox.CopyVerify(&dst, bytes.NewReader(data), sum[:], sha256.New())
+	if n != int64(len(data)) || err != nil || dst.String() != string(data) {
+		t.Fatalf("match: n=%d err=%v dst=%q", n, err, dst.String())
+	}
+
+	n, err = iox.CopyVerify(&sliceWriter{}, bytes.NewReader([]byte("tampered")), sum[:], sha256.New())
+	if n != 8 || !errors.Is(err, iox.ErrChecksumMismatch) {
+		t.Fatalf("mismatch: n=%d err=%v", n, err)
+	}
+
+	// Semantic stops are returned before verification.
+	n, err = iox.CopyVerify(&sliceWriter{}, &dataThenAlwaysWBReader{data: []byte("ab")}, sum[:], sha256.New())
+	if n != 2 || err != iox.ErrWouldBlock {
+		t.Fatalf("would block: n=%d err=%v", n, err)
+	}
+}
+
+func TestCopyDuplexStep(t *testing.T) {
+	// Read blocks, write ready.
+	var dst sliceWriter
+	rn, wn, rErr, wErr := iox.CopyDuplexStep(&dst, errReaderAlwaysWB{})
+	if rn != 0 || wn != 0 || rErr != iox.ErrWouldBlock || wErr != nil {
+		t.Fatalf("read blocks=(%d,%d,%v,%v)", rn, wn, rErr, wErr)
+	}
+
+	// Write blocks, read ready: unwritten bytes are rolled back.
+	src := bytes.NewReader([]byte("hello"))
+	rn, wn, rErr, wErr = iox.CopyDuplexStep(wbOnlyWriter{}, src)
+	if rn != 5 || wn != 0 || rErr != nil || wErr != iox.ErrWouldBlock {
+		t.Fatalf("write blocks=(%d,%d,%v,%v)", rn, wn, rErr, wErr)
+	}
+	if src.Len() != 5 {
+		t.Fatalf("rollback left %d unread, want 5", src.Len())
+	}
+	gate := &gateWriter{quota: 2}
+	if rn, wn, _, wErr = iox.CopyDuplexStep(gate, src); rn != 5 || wn != 2 || wErr != iox.ErrWouldBlock {
+		t.Fatalf("partial=(%d,%d,%v)", rn, wn, wErr)
+	}
+	if src.Len() != 3 || gate.buf.String() != "he" {
+		t.Fatalf("unread=%d dst=%q", src.Len(), gate.buf.String())
+	}
+
+	// Both block: the empty write probe reports the writer side.
+	rn, wn, rErr, wErr = iox.CopyDuplexStep(wbOnlyWriter{}, errReaderAlwaysWB{})
+	if rn != 0 || wn != 0 || rErr != iox.ErrWouldBlock || wErr != iox.ErrWouldBlock {
+		t.Fatalf("both block=(%d,%d,%v,%v)", rn, wn, rErr, wErr)
+	}
+
+	// Without a Seeker, unwritten bytes cannot be recovered.
+	_, _, _, wErr = iox.CopyDuplexStep(wbOnlyWriter{}, &plainReader{data: []byte("x")})
+	if wErr != iox.ErrNoSeeker {
+		t.Fatalf("non-seekable: wErr=%v want ErrNoSeeker", wErr)
+	}
+
+	// Once the read side has ended, dst is not probed.
+	rn, wn, rErr, wErr = iox.CopyDuplexStep(wbOnlyWriter{}, bytes.NewReader(nil))
+	if rn != 0 || wn != 0 || rErr != io.EOF || wErr != nil {
+		t.Fatalf("read ended=(%d,%d,%v,%v)", rn, wn, rErr, wErr)
+	}
+
+	// The staging buffer is pooled, not allocated per step.
+	if allocs := testing.AllocsPerRun(100, func() {
+		iox.CopyDuplexStep(wbOnlyWriter{}, errReaderAlwaysWB{})
+	}); allocs != 0 {
+		t.Fatalf("allocs per step=%v want 0", allocs)
+	}
+}
+
+func TestDedupWriter(t *testing.T) {
+	var sink chunkRecorder
+	dw := iox.DedupWriter(&sink)
+	for _, f := range []string{"ping", "ping", "pong", "ping", "ping"} {
+		if n, err := dw.Write([]byte(f)); n != len(f) || err != nil {
+			t.Fatalf("write %q: n=%d err=%v", f, n, err)
+		}
+	}
+	if got := fmt.Sprint(sink.chunks); got != "[ping pong ping]" {
+		t.Fatalf("forwarded=%s want [ping pong ping]", got)
+	}
+
+	// A partially accepted frame is not remembered.
+	gate := &gateWriter{quota: 2}
+	dw = iox.DedupWriter(gate)
+	if n, err := dw.Write([]byte("abcd")); n != 2 || err != iox.ErrWouldBlock {
+		t.Fatalf("partial: n=%d err=%v", n, err)
+	}
+	gate.quota = 100
+	for range 2 {
+		if n, err := dw.Write([]byte("abcd")); n != 4 || err != nil {
+			t.Fatalf("resend: n=%d err=%v", n, err)
+		}
+	}
+	if gate.buf.String() != "ababcd" {
+		t.Fatalf("sink=%q want ababcd", gate.buf.String())
+	}
+}
+
+func TestKeepAliveReader(t *testing.T) {
+	src := &scriptedReader{steps: []scriptStep{{b: []byte("ab"), err: io.EOF}, {b: []byte("cd")}}}
+	ka := iox.KeepAliveReader(src)
+	var dst bytes.Buffer
+	if n, err := iox.Copy(&dst, ka); n != 2 || err != iox.ErrMore {
+		t.Fatalf("burst 1=(%d,%v) want (2,ErrMore)", n, err)
+	}
+	if n, err := iox.Copy(&dst, ka); n != 2 || err != iox.ErrMore {
+		t.Fatalf("burst 2=(%d,%v) want (2,ErrMore)", n, err)
+	}
+	if dst.String() != "abcd" {
+		t.Fatalf("dst=%q", dst.String())
+	}
+
+	ka.Done()
+	var p [4]byte
+	if n, err := ka.Read(p[:]); n != 0 || err != io.EOF {
+		t.Fatalf("after Done=(%d,%v) want (0,EOF)", n, err)
+	}
+	src.steps = []scriptStep{{b: []byte("ef"), err: io.EOF}}
+	src.i = 0
+	if n, err := iox.Copy(&dst, ka); n != 2 || err != nil || dst.String() != "abcdef" {
+		t.Fatalf("final=(%d,%v) dst=%q", n, err, dst.String())
+	}
+}
+
+func TestChunkWriter(t *testing.T) {
+	side := &chunkRecorder{}
+	cw := iox.ChunkWriter(side, 4)
+	if n, err := cw.Write([]byte("abcdefghij")); n != 10 || err != nil {
+		t.Fatalf("split=(%d,%v)", n, err)
+	}
+	if got := fmt.Sprint(side.chunks); got != "[abcd efgh ij]" {
+		t.Fatalf("chunks=%s", got)
+	}
+
+	// A mid-chunk ErrWouldBlock stops with the committed count.
+	gw := &gateWriter{quota: 6}
+	cw = iox.ChunkWriter(gw, 4)
+	if n, err := cw.Write([]byte("abcdefghij")); n != 6 || err != iox.ErrWouldBlock {
+		t.Fatalf("blocked=(%d,%v) want (6,ErrWouldBlock)", n, err)
+	}
+	if gw.buf.String() != "abcdef" {
+		t.Fatalf("sink=%q", gw.buf.String())
+	}
+}
+
+func TestCopyAtomic(t *testing.T) {
+	src := bytes.NewReader([]byte("0123456789"))
+	src.Seek(2, io.SeekStart)
+
+	// A generic sink error restores the initial offset.
+	boom := errors.New("sink failed")
+	n, err := iox.CopyAtomic(errWriter{n: 3, err: boom}, src)
+	if n != 3 || err != boom {
+		t.Fatalf("failure=(%d,%v) want (3,boom)", n, err)
+	}
+	if off, _ := src.Seek(0, io.SeekCurrent); off != 2 {
+		t.Fatalf("offset after failure=%d want 2", off)
+	}
+
+	// A short write is a failure too.
+	if _, err := iox.CopyAtomic(shortWriter{limit: 4}, src); err != io.ErrShortWrite {
+		t.Fatalf("short write err=%v", err)
+	}
+	if off, _ := src.Seek(0, io.SeekCurrent); off != 2 {
+		t.Fatalf("offset after short write=%d want 2", off)
+	}
+
+	// On success the offset reflects every byte read.
+	var dst sliceWriter
+	n, err = iox.CopyAtomic(&dst, src)
+	if n != 8 || err != nil || string(dst.data) != "23456789" {
+		t.Fatalf("success=(%d,%v,%q)", n, err, dst.data)
+	}
+	if off, _ := src.Seek(0, io.SeekCurrent); off != 10 {
+		t.Fatalf("offset after success=%d want 10", off)
+	}
+}
+
+func TestCopyLengthPrefixed(t *testing.T) {
+	var dst sliceWriter
+	n, err := iox.CopyLengthPrefixed(&dst, &plainReader{data: []byte("helloEXTRA")}, 5)
+	if n != 5 || err != nil || string(dst.data) != "\x00\x00\x00\x05hello" {
+		t.Fatalf("clean=(%d,%v,%q)", n, err, dst.data)
+	}
+
+	// ErrWouldBlock mid-prefix is retried; the body follows a whole prefix.
+	pw := &partialThenWBWriter{k: 2}
+	n, err = iox.CopyLengthPrefixed(pw, &plainReader{data: []byte("ab")}, 2)
+	if n != 2 || err != nil || pw.buf.String() != "\x00\x00\x00\x02ab" {
+		t.Fatalf("blocked prefix=(%d,%v,%q)", n, err, pw.buf.String())
+	}
+
+	dst.data = nil
+	n, err = iox.CopyLengthPrefixed(&dst, &plainReader{data: []byte("abc")}, 10)
+	if n != 3 || err != io.ErrUnexpectedEOF || string(dst.data) != "\x00\x00\x00\x0aabc" {
+		t.Fatalf("short=(%d,%v,%q) want (3,ErrUnexpectedEOF)", n, err, dst.data)
+	}
+}
@@ -0,0 +1,184 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package ioxtest provides scripted Readers and Writers for testing code
+// against iox semantics.
+//
+// A ScriptedReader or ScriptedWriter replays a fixed sequence of results,
+// including ErrWouldBlock and ErrMore, so implementers of non-blocking
+// Readers/Writers and callers of the iox.Copy family can exercise every
+// control-flow branch deterministically. AssertCopy and AssertCopyPolicy
+// check the (written, err) contract of a copy in one call.
+//
+// The types in this package are not safe for concurrent use.
+package ioxtest
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"code.hybscloud.com/iox"
+)
+
+// Step is one scripted Read result: Data is delivered to the caller and Err
+// is returned together with the last byte of Data.
+//
+// If Data does not fit in the caller's buffer, it is delivered across several
+// Read calls; the intermediate calls return a nil error and Err is returned
+// only with the final piece. A Step with no Data returns (0, Err), so
+// Step{} scripts a (0, nil) read.
+type Step struct {
+	Data []byte
+	Err  error
+}
+
+// Data returns a Step delivering s with a nil error.
+func Data(s string) Step { return Step{Data: []byte(s)} }
+
+// More returns a Step delivering s with iox.ErrMore.
+func More(s string) Step { return Step{Data: []byte(s), Err: iox.ErrMore} }
+
+// WouldBlock returns a Step that reports (0, iox.ErrWouldBlock).
+func WouldBlock() Step { return Step{Err: iox.ErrWouldBlock} }
+
+// ScriptedReader is a Reader that replays a sequence of Steps and then
+// reports io.EOF on every subsequent call.
+type ScriptedReader struct {
+	steps []Step
+	i     int // current step
+	off   int // bytes of steps[i].Data already delivered
+	calls int
+}
+
+// NewScriptedReader returns a ScriptedReader replaying steps in order.
+func NewScriptedReader(steps ...Step) *ScriptedReader {
+	return &ScriptedReader{steps: steps}
+}
+
+// Read delivers the next scripted result.
+//
+// A zero-length p returns (0, nil) without consuming the script.
+func (r *ScriptedReader) Read(p []byte) (n int, err error) {
+	r.calls++
+	if r.i >= len(r.steps) {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	st := r.steps[r.i]
+	n = copy(p, st.Data[r.off:])
+	r.off += n
+	if r.off < len(st.Data) {
+		return n, nil
+	}
+	r.i++
+	r.off = 0
+	return n, st.Err
+}
+
+// Calls returns the number of Read calls made so far, including calls after
+// the script was exhausted.
+func (r *ScriptedReader) Calls() int { return r.calls }
+
+// Done reports whether every scripted Step has been delivered.
+func (r *ScriptedReader) Done() bool { return r.i >= len(r.steps) }
+
+// All is the WriteStep.N value that accepts the whole buffer.
+const All = -1
+
+// WriteStep is one scripted Write result: at most N bytes of p are accepted
+// (All, or any negative N, accepts all of p) and Err is returned.
+//
+// WriteStep{N: 0} with a nil Err scripts a (0, nil) write, which the iox
+// engines report as io.ErrShortWrite.
+type WriteStep struct {
+	N   int
+	Err error
+}
+
+// Accept returns a WriteStep accepting at most n bytes with a nil error.
+func Accept(n int) WriteStep { return WriteStep{N: n} }
+
+// AcceptMore returns a WriteStep accepting at most n bytes with iox.ErrMore.
+func AcceptMore(n int) WriteStep { return WriteStep{N: n, Err: iox.ErrMore} }
+
+// BlockAfter returns a WriteStep accepting at most n bytes with
+// iox.ErrWouldBlock. BlockAfter(0) scripts a pure would-block.
+func BlockAfter(n int) WriteStep { return WriteStep{N: n, Err: iox.ErrWouldBlock} }
+
+// ScriptedWriter is a Writer that replays a sequence of WriteSteps and
+// records the bytes it accepts. Once the script is exhausted, every Write
+// accepts all of p with a nil error.
+type ScriptedWriter struct {
+	steps []WriteStep
+	i     int
+	buf   bytes.Buffer
+	calls int
+}
+
+// NewScriptedWriter returns a ScriptedWriter replaying steps in order.
+func NewScriptedWriter(steps ...WriteStep) *ScriptedWriter {
+	return &ScriptedWriter{steps: steps}
+}
+
+// Write accepts bytes according to the next scripted WriteStep.
+func (w *ScriptedWriter) Write(p []byte) (n int, err error) {
+	w.calls++
+	n = len(p)
+	if w.i < len(w.steps) {
+		st := w.steps[w.i]
+		w.i++
+		if st.N >= 0 && st.N < n {
+			n = st.N
+		}
+		err = st.Err
+	}
+	w.buf.Write(p[:n])
+	return n, err
+}
+
+// Bytes returns the bytes accepted so far. The slice aliases the writer's
+// buffer and is valid until the next Write.
+func (w *ScriptedWriter) Bytes() []byte { return w.buf.Bytes() }
+
+// String returns the bytes accepted so far as a string.
+func (w *ScriptedWriter) String() string { return w.buf.String() }
+
+// Calls returns the number of Write calls made so far.
+func (w *ScriptedWriter) Calls() int { return w.calls }
+
+// Done reports whether every scripted WriteStep has been consumed.
+func (w *ScriptedWriter) Done() bool { return w.i >= len(w.steps) }
+
+// AssertCopy runs iox.Copy(dst, src) and fails t unless it returns
+// (wantN, err) with err matching wantErr. A nil wantErr requires a nil err;
+// otherwise errors.Is(err, wantErr) must hold, so wrapped semantic errors
+// match their sentinels. The copy result is returned for further checks.
+func AssertCopy(t testing.TB, dst iox.Writer, src iox.Reader, wantN int64, wantErr error) (int64, error) {
+	t.Helper()
+	n, err := iox.Copy(dst, src)
+	check(t, "Copy", n, err, wantN, wantErr)
+	return n, err
+}
+
+// AssertCopyPolicy is like AssertCopy but runs iox.CopyPolicy with policy.
+func AssertCopyPolicy(t testing.TB, dst iox.Writer, src iox.Reader, policy iox.SemanticPolicy, wantN int64, wantErr error) (int64, error) {
+	t.Helper()
+	n, err := iox.CopyPolicy(dst, src, policy)
+	check(t, "CopyPolicy", n, err, wantN, wantErr)
+	return n, err
+}
+
+func check(t testing.TB, name string, n int64, err error, wantN int64, wantErr error) {
+	t.Helper()
+	if wantErr == nil && err != nil || wantErr != nil && !errors.Is(err, wantErr) {
+		t.Errorf("%s: err=%v want %v", name, err, wantErr)
+	}
+	if n != wantN {
+		t.Errorf("%s: written=%d want %d", name, n, wantN)
+	}
+}
@@ -0,0 +1,112 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ioxtest_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"code.hybscloud.com/iox"
+	"code.hybscloud.com/iox/ioxtest"
+)
+
+func TestScriptedReader_ErrMoreFeedsCopy(t *testing.T) {
+	src := ioxtest.NewScriptedReader(
+		ioxtest.More("hello"),
+		ioxtest.More("world"),
+		ioxtest.WouldBlock(),
+		ioxtest.Data("iox"),
+	)
+	var dst bytes.Buffer
+
+	ioxtest.AssertCopy(t, &dst, src, 5, iox.ErrMore)
+	ioxtest.AssertCopy(t, &dst, src, 5, iox.ErrMore)
+	ioxtest.AssertCopy(t, &dst, src, 0, iox.ErrWouldBlock)
+	ioxtest.AssertCopy(t, &dst, src, 3, nil)
+	if dst.String() != "helloworldiox" {
+		t.Fatalf("dst=%q", dst.String())
+	}
+	if !src.Done() {
+		t.Fatal("script not fully consumed")
+	}
+}
+
+func TestScriptedReader_SplitsLargeStep(t *testing.T) {
+	src := ioxtest.NewScriptedReader(ioxtest.Step{Data: []byte("abcdef"), Err: iox.ErrMore})
+	p := make([]byte, 4)
+	if n, err := src.Read(p); n != 4 || err != nil {
+		t.Fatalf("first=(%d,%v) want (4,nil)", n, err)
+	}
+	if n, err := src.Read(p); n != 2 || err != iox.ErrMore || string(p[:n]) != "ef" {
+		t.Fatalf("second=(%d,%v,%q) want (2,ErrMore,\"ef\")", n, err, p[:n])
+	}
+	if n, err := src.Read(p); n != 0 || err != io.EOF {
+		t.Fatalf("after script=(%d,%v) want (0,EOF)", n, err)
+	}
+	if n, err := src.Read(nil); n != 0 || err != io.EOF {
+		t.Fatalf("exhausted empty read=(%d,%v)", n, err)
+	}
+	if src.Calls() != 4 {
+		t.Fatalf("Calls()=%d want 4", src.Calls())
+	}
+}
+
+func TestScriptedWriter_PolicyRetry(t *testing.T) {
+	dst := ioxtest.NewScriptedWriter(ioxtest.BlockAfter(2), ioxtest.BlockAfter(0), ioxtest.Accept(1))
+	src := ioxtest.NewScriptedReader(ioxtest.Data("abcdef"))
+
+	ioxtest.AssertCopyPolicy(t, dst, src, iox.YieldPolicy{}, 6, nil)
+	if dst.String() != "abcdef" {
+		t.Fatalf("dst=%q", dst.String())
+	}
+	if !dst.Done() || dst.Calls() != 4 {
+		t.Fatalf("Done()=%v Calls()=%d", dst.Done(), dst.Calls())
+	}
+}
+
+func TestScriptedWriter_SeekerRollback(t *testing.T) {
+	dst := ioxtest.NewScriptedWriter(ioxtest.BlockAfter(3))
+	src := struct {
+		io.Reader
+		io.Seeker
+	}{}
+	br := bytes.NewReader([]byte("abcdef"))
+	src.Reader, src.Seeker = br, br
+
+	ioxtest.AssertCopy(t, dst, src, 3, iox.ErrWouldBlock)
+	ioxtest.AssertCopy(t, dst, src, 3, nil)
+	if dst.String() != "abcdef" {
+		t.Fatalf("dst=%q", dst.String())
+	}
+}
+
+// recTB records failures instead of failing the enclosing test.
+type recTB struct {
+	testing.TB
+	msgs []string
+}
+
+func (r *recTB) Helper() {}
+
+func (r *recTB) Errorf(format string, args ...any) {
+	r.msgs = append(r.msgs, fmt.Sprintf(format, args...))
+}
+
+func TestAssertCopy_ReportsMismatch(t *testing.T) {
+	rec := &recTB{}
+	src := ioxtest.NewScriptedReader(ioxtest.Step{Data: []byte("ab"), Err: fmt.Errorf("ring: %w", iox.ErrMore)})
+	n, err := ioxtest.AssertCopy(rec, io.Discard, src, 2, iox.ErrMore)
+	if len(rec.msgs) != 0 || n != 2 || !errors.Is(err, iox.ErrMore) {
+		t.Fatalf("wrapped ErrMore should match: msgs=%v n=%d err=%v", rec.msgs, n, err)
+	}
+
+	ioxtest.AssertCopy(rec, io.Discard, ioxtest.NewScriptedReader(ioxtest.Data("abc")), 2, iox.ErrWouldBlock)
+	if len(rec.msgs) != 2 {
+		t.Fatalf("want 2 failures, got %v", rec.msgs)
+	}
+}
@@ -0,0 +1,69 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iox
+
+import (
+	"errors"
+	"syscall"
+)
+
+// NonBlockingReader wraps r so that EAGAIN / EWOULDBLOCK errors returned by
+// r.Read are reported as ErrWouldBlock.
+//
+// Use it around non-blocking file descriptors whose Read surfaces raw errno
+// values, so that the Copy family and policies recognize the would-block
+// semantic. Byte counts and all other errors are passed through unchanged.
+func NonBlockingReader(r Reader) Reader { return nonBlockingReader{r: r} }
+
+// NonBlockingWriter wraps w so that EAGAIN / EWOULDBLOCK errors returned by
+// w.Write are reported as ErrWouldBlock. Byte counts and all other errors are
+// passed through unchanged.
+func NonBlockingWriter(w Writer) Writer { return nonBlockingWriter{w: w} }
+
+type nonBlockingReader struct{ r Reader }
+
+func (nb nonBlockingReader) Read(p []byte) (int, error) {
+	n, err := nb.r.Read(p)
+	return n, mapEAGAIN(err)
+}
+
+type nonBlockingWriter struct{ w Writer }
+
+func (nb nonBlockingWriter) Write(p []byte) (int, error) {
+	n, err := nb.w.Write(p)
+	return n, mapEAGAIN(err)
+}
+
+// mapEAGAIN rewrites EAGAIN / EWOULDBLOCK (including wrapped forms such as
+// *os.PathError) to ErrWouldBlock.
+func mapEAGAIN(err error) error {
+	if err == nil || err == ErrWouldBlock {
+		return err
+	}
+	if errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EWOULDBLOCK) {
+		return ErrWouldBlock
+	}
+	return err
+}
+
+// ShortWriteAsWouldBlock wraps w so that backpressure expressed as a short
+// write is reported as ErrWouldBlock: a Write that accepts fewer than len(p)
+// bytes with a nil error (including zero bytes), or that returns
+// io.ErrShortWrite, returns (n, ErrWouldBlock) instead.
+//
+// This lets Copy's Seeker rollback and the policy-driven retry machinery engage
+// for sinks that never return ErrWouldBlock themselves, rather than aborting
+// with io.ErrShortWrite. All other results are passed through unchanged.
+func ShortWriteAsWouldBlock(w Writer) Writer { return shortWriteAsWouldBlock{w: w} }
+
+type shortWriteAsWouldBlock struct{ w Writer }
+
+func (s shortWriteAsWouldBlock) Write(p []byte) (int, error) {
+	n, err := s.w.Write(p)
+	if (err == nil && n < len(p)) || errors.Is(err, ErrShortWrite) {
+		return n, ErrWouldBlock
+	}
+	return n, err
+}
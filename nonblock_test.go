@@ -0,0 +1,166 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iox_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"syscall"
+	"testing"
+
+	"code.hybscloud.com/iox"
+)
+
+// -----------------------------------------------------------------------------
+// NonBlockingReader and NonBlockingWriter tests
+// -----------------------------------------------------------------------------
+
+// errnoReader returns data (if any) with the configured error.
+type errnoReader struct {
+	data []byte
+	err  error
+}
+
+func (r errnoReader) Read(p []byte) (int, error) { return copy(p, r.data), r.err }
+
+func TestNonBlockingReader_EAGAINNoData(t *testing.T) {
+	r := iox.NonBlockingReader(errnoReader{err: syscall.EAGAIN})
+	var b [4]byte
+	n, err := r.Read(b[:])
+	if err != iox.ErrWouldBlock || n != 0 {
+		t.Fatalf("want (0, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+}
+
+func TestNonBlockingReader_EAGAINWithPartialData(t *testing.T) {
+	r := iox.NonBlockingReader(errnoReader{data: []byte("ab"), err: syscall.EWOULDBLOCK})
+	var b [4]byte
+	n, err := r.Read(b[:])
+	if err != iox.ErrWouldBlock || n != 2 || string(b[:n]) != "ab" {
+		t.Fatalf("want (2, ErrWouldBlock) got (%d, %v) %q", n, err, string(b[:n]))
+	}
+}
+
+func TestNonBlockingReader_WrappedEAGAIN(t *testing.T) {
+	perr := &fs.PathError{Op: "read", Path: "/dev/x", Err: syscall.EAGAIN}
+	r := iox.NonBlockingReader(errnoReader{err: perr})
+	var b [1]byte
+	if _, err := r.Read(b[:]); err != iox.ErrWouldBlock {
+		t.Fatalf("want ErrWouldBlock got %v", err)
+	}
+}
+
+func TestNonBlockingReader_OtherErrorsUntouched(t *testing.T) {
+	for _, want := range []error{nil, iox.EOF, syscall.ECONNRESET, errors.New("boom")} {
+		r := iox.NonBlockingReader(errnoReader{data: []byte("x"), err: want})
+		var b [1]byte
+		n, err := r.Read(b[:])
+		if err != want || n != 1 {
+			t.Fatalf("want (1, %v) got (%d, %v)", want, n, err)
+		}
+	}
+}
+
+func TestNonBlockingReader_CopyUnderstandsEAGAIN(t *testing.T) {
+	var dst sliceWriter
+	n, err := iox.Copy(&dst, iox.NonBlockingReader(errnoReader{data: []byte("hi"), err: syscall.EAGAIN}))
+	if !errors.Is(err, iox.ErrWouldBlock) || n != 2 || string(dst.data) != "hi" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, string(dst.data))
+	}
+}
+
+func TestNonBlockingWriter_EAGAIN(t *testing.T) {
+	w := iox.NonBlockingWriter(errWriter{n: 1, err: syscall.EAGAIN})
+	n, err := w.Write([]byte("abc"))
+	if err != iox.ErrWouldBlock || n != 1 {
+		t.Fatalf("want (1, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	boom := errors.New("boom")
+	w = iox.NonBlockingWriter(errWriter{n: 0, err: boom})
+	if n, err := w.Write([]byte("abc")); err != boom || n != 0 {
+		t.Fatalf("want (0, boom) got (%d, %v)", n, err)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// ShortWriteAsWouldBlock tests
+// -----------------------------------------------------------------------------
+
+// cappedWriter accepts at most limit bytes per Write with a nil error.
+type cappedWriter struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func (w *cappedWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		p = p[:w.limit]
+	}
+	return w.buf.Write(p)
+}
+
+func TestShortWriteAsWouldBlock_LosslessCopy(t *testing.T) {
+	data := []byte("the quick brown fox")
+	srcs := map[string]iox.Reader{
+		"WriterTo": bytes.NewReader(data),
+		"Seeker":   &seekOnlyReader{r: bytes.NewReader(data)},
+	}
+	for name, src := range srcs {
+		t.Run(name, func(t *testing.T) {
+			cw := &cappedWriter{limit: 4}
+			dst := iox.ShortWriteAsWouldBlock(cw)
+			var total int64
+			for i := 0; ; i++ {
+				n, err := iox.Copy(dst, src)
+				total += n
+				if err == nil {
+					break
+				}
+				if err != iox.ErrWouldBlock || i > len(data) {
+					t.Fatalf("round %d: err=%v", i, err)
+				}
+			}
+			if total != int64(len(data)) || cw.buf.String() != string(data) {
+				t.Fatalf("total=%d dst=%q", total, cw.buf.String())
+			}
+		})
+	}
+}
+
+func TestShortWriteAsWouldBlock_Mapping(t *testing.T) {
+	w := iox.ShortWriteAsWouldBlock(&cappedWriter{limit: 0})
+	if n, err := w.Write([]byte("x")); n != 0 || err != iox.ErrWouldBlock {
+		t.Fatalf("zero write=(%d,%v) want (0,ErrWouldBlock)", n, err)
+	}
+	w = iox.ShortWriteAsWouldBlock(errWriterFixed{n: 1, err: io.ErrShortWrite})
+	if n, err := w.Write([]byte("xy")); n != 1 || err != iox.ErrWouldBlock {
+		t.Fatalf("ErrShortWrite=(%d,%v) want (1,ErrWouldBlock)", n, err)
+	}
+	boom := errors.New("boom")
+	w = iox.ShortWriteAsWouldBlock(errWriterFixed{n: 1, err: boom})
+	if n, err := w.Write([]byte("xy")); n != 1 || err != boom {
+		t.Fatalf("failure=(%d,%v) want (1,boom)", n, err)
+	}
+	w = iox.ShortWriteAsWouldBlock(&cappedWriter{limit: 8})
+	if n, err := w.Write([]byte("xy")); n != 2 || err != nil {
+		t.Fatalf("full write=(%d,%v)", n, err)
+	}
+}
+
+// seekOnlyReader exposes Read and Seek but hides WriterTo.
+type seekOnlyReader struct{ r *bytes.Reader }
+
+func (s *seekOnlyReader) Read(p []byte) (int, error) { return s.r.Read(p) }
+
+func (s *seekOnlyReader) Seek(off int64, whence int) (int64, error) { return s.r.Seek(off, whence) }
+
+type errWriterFixed struct {
+	n   int
+	err error
+}
+
+func (w errWriterFixed) Write(p []byte) (int, error) { return w.n, w.err }
@@ -4,7 +4,13 @@
 
 package iox
 
-import "runtime"
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
 
 // Op identifies where a semantic signal (ErrWouldBlock / ErrMore) came from.
 //
@@ -24,6 +30,10 @@ const (
 
 	OpTeeWriterPrimaryWrite
 	OpTeeWriterTeeWrite
+
+	// OpNone is not an engine step; it is reported where no step applies,
+	// e.g., by CopyWhere for a copy that completed cleanly.
+	OpNone
 )
 
 func (op Op) String() string {
@@ -44,6 +54,8 @@ func (op Op) String() string {
 		return "TeeWriterPrimaryWrite"
 	case OpTeeWriterTeeWrite:
 		return "TeeWriterTeeWrite"
+	case OpNone:
+		return "None"
 	default:
 		return "Op(unknown)"
 	}
@@ -180,3 +192,530 @@ func (YieldOnWriteWouldBlockPolicy) OnWouldBlock(op Op) PolicyAction {
 }
 
 func (YieldOnWriteWouldBlockPolicy) OnMore(Op) PolicyAction { return PolicyReturn }
+
+// NotifyPolicy retries on ErrWouldBlock like YieldPolicy, but waits for an
+// event-loop readiness signal instead of spinning: Yield blocks in
+// WaitReady(op) until the descriptor behind op is ready (e.g., by waiting on a
+// sync.Cond or a channel that the event loop signals).
+//
+// ErrMore returns by default; set RetryMore to keep consuming completions.
+// A nil WaitReady falls back to runtime.Gosched.
+type NotifyPolicy struct {
+	// WaitReady blocks until op can make progress.
+	WaitReady func(op Op)
+
+	// RetryMore makes OnMore return PolicyRetry instead of PolicyReturn.
+	RetryMore bool
+}
+
+func (p NotifyPolicy) Yield(op Op) {
+	if p.WaitReady != nil {
+		p.WaitReady(op)
+		return
+	}
+	runtime.Gosched()
+}
+
+func (NotifyPolicy) OnWouldBlock(Op) PolicyAction { return PolicyRetry }
+
+func (p NotifyPolicy) OnMore(Op) PolicyAction {
+	if p.RetryMore {
+		return PolicyRetry
+	}
+	return PolicyReturn
+}
+
+// SchedulerPolicy returns a policy for runtimes that multiplex fibers (or
+// goroutines) over an external scheduler: instead of spinning on the copying
+// goroutine, every ErrWouldBlock retry hands readiness waiting to park.
+//
+// Contract for park:
+//   - park(op) is called on the copying goroutine after the engine chose to
+//     retry op; it should submit the current fiber to the scheduler (e.g.,
+//     register interest in the descriptor behind op) and suspend it.
+//   - park must return only when the side named by op can make progress. A
+//     park that returns early is safe, but the engine immediately retries,
+//     would-blocks again, and parks again, degrading into a busy loop.
+//   - park must not call back into the same engine.
+//
+// ErrMore returns to the caller, as with YieldPolicy. SchedulerPolicy(park)
+// is NotifyPolicy{WaitReady: park}; a nil park falls back to runtime.Gosched.
+func SchedulerPolicy(park func(op Op)) SemanticPolicy {
+	return NotifyPolicy{WaitReady: park}
+}
+
+// AdaptivePolicy returns a policy that retries on ErrWouldBlock and escalates
+// how it waits: the first spinsBeforeBackoff consecutive yields for an Op call
+// runtime.Gosched, later ones sleep via a per-Op Backoff. ErrMore returns, as
+// with YieldPolicy.
+//
+// This avoids both burning CPU with pure Gosched on long stalls and paying
+// Backoff latency on transient ones. The consecutive count and Backoff of an
+// Op restart when OnMore or Progress reports progress on it, or on Reset.
+func AdaptivePolicy(spinsBeforeBackoff int) *AdaptiveYieldPolicy {
+	return &AdaptiveYieldPolicy{spins: spinsBeforeBackoff}
+}
+
+// PerOpBackoffPolicy returns a policy that retries on ErrWouldBlock and waits
+// with an independent Backoff per Op, so read-side and write-side stalls
+// escalate along separate curves. An Op's Backoff restarts when OnMore or
+// Progress reports progress on it, leaving the other Ops untouched.
+//
+// It is AdaptivePolicy(0): every Yield sleeps, with no Gosched spins first.
+// Use SetSleepFunc to route the waits through a fake clock.
+func PerOpBackoffPolicy() *AdaptiveYieldPolicy { return AdaptivePolicy(0) }
+
+// AdaptiveYieldPolicy is the policy returned by AdaptivePolicy. Use it by
+// pointer. It is not safe for concurrent use.
+type AdaptiveYieldPolicy struct {
+	spins int
+	sleep func(time.Duration)
+	ops   map[Op]*adaptiveState
+}
+
+type adaptiveState struct {
+	yields int
+	b      Backoff
+}
+
+func (p *AdaptiveYieldPolicy) state(op Op) *adaptiveState {
+	st := p.ops[op]
+	if st == nil {
+		if p.ops == nil {
+			p.ops = make(map[Op]*adaptiveState)
+		}
+		st = &adaptiveState{}
+		st.b.SetSleepFunc(p.sleep)
+		p.ops[op] = st
+	}
+	return st
+}
+
+// Yield spins with runtime.Gosched for the first spinsBeforeBackoff
+// consecutive yields on op, then waits with op's Backoff.
+func (p *AdaptiveYieldPolicy) Yield(op Op) {
+	st := p.state(op)
+	st.yields++
+	if st.yields <= p.spins {
+		runtime.Gosched()
+		return
+	}
+	st.b.Wait()
+}
+
+// OnWouldBlock always retries.
+func (p *AdaptiveYieldPolicy) OnWouldBlock(Op) PolicyAction { return PolicyRetry }
+
+// OnMore returns and, since ErrMore implies progress, restarts op's escalation.
+func (p *AdaptiveYieldPolicy) OnMore(op Op) PolicyAction {
+	if st := p.ops[op]; st != nil {
+		st.yields = 0
+		st.b.Reset()
+	}
+	return PolicyReturn
+}
+
+// Progress implements ProgressReporter: a step that moved bytes restarts op's
+// escalation.
+func (p *AdaptiveYieldPolicy) Progress(op Op, n int) {
+	if st := p.ops[op]; st != nil && n > 0 {
+		st.yields = 0
+		st.b.Reset()
+	}
+}
+
+// Reset restarts the escalation for every Op.
+func (p *AdaptiveYieldPolicy) Reset() {
+	for _, st := range p.ops {
+		st.yields = 0
+		st.b.Reset()
+	}
+}
+
+// SetSleepFunc replaces the sleep used by the per-Op Backoffs; see
+// Backoff.SetSleepFunc. A nil f restores time.Sleep.
+func (p *AdaptiveYieldPolicy) SetSleepFunc(f func(time.Duration)) {
+	p.sleep = f
+	for _, st := range p.ops {
+		st.b.SetSleepFunc(f)
+	}
+}
+
+// NoProgressPolicy is an optional extension of SemanticPolicy that lets a
+// policy cap retries when an engine keeps stalling without progress (e.g., a
+// reader stuck returning (0, ErrWouldBlock)).
+//
+// The copy engines detect it by type assertion. After a semantic error from a
+// step that moved no bytes has been answered with PolicyRetry, the engine calls
+// OnNoProgress with the number of consecutive zero-progress retries (starting
+// at 1) before yielding. PolicyReturn stops the engine, which returns the
+// semantic error as though the policy had returned PolicyReturn. Any progress
+// resets the count. Policies that do not implement it retry as before.
+type NoProgressPolicy interface {
+	SemanticPolicy
+	OnNoProgress(op Op, consecutive int) PolicyAction
+}
+
+// ProgressReporter is an optional extension of SemanticPolicy that lets a
+// policy observe how many bytes each engine step moved, e.g., to adapt its
+// waiting to the observed throughput.
+//
+// copyBufferPolicy and the policy-aware Tee adapters detect it by type
+// assertion and call Progress(op, n) after every read or write step,
+// including steps that moved zero bytes or returned a semantic error, before
+// consulting OnWouldBlock / OnMore. Policies that do not implement it are
+// unaffected.
+type ProgressReporter interface {
+	SemanticPolicy
+	Progress(op Op, n int)
+}
+
+// progress forwards a step's byte count to p if it is a ProgressReporter.
+func progress(p SemanticPolicy, op Op, n int) {
+	if pr, ok := p.(ProgressReporter); ok {
+		pr.Progress(op, n)
+	}
+}
+
+// forwardingPolicy is embedded by policy wrappers so that the optional
+// ProgressReporter and NoProgressPolicy extensions of the wrapped policy stay
+// visible to the engines' type assertions. OnNoProgress retries when the
+// wrapped policy does not implement NoProgressPolicy, as the engines would.
+type forwardingPolicy struct{ SemanticPolicy }
+
+func (p forwardingPolicy) Progress(op Op, n int) { progress(p.SemanticPolicy, op, n) }
+
+func (p forwardingPolicy) OnNoProgress(op Op, consecutive int) PolicyAction {
+	if np, ok := p.SemanticPolicy.(NoProgressPolicy); ok {
+		return np.OnNoProgress(op, consecutive)
+	}
+	return PolicyRetry
+}
+
+// DeadlinePolicy returns a policy that defers to inner until the wall-clock
+// deadline passes, and returns PolicyReturn for every decision afterwards.
+//
+// Use it to bound how long a retrying engine keeps waiting: once the deadline
+// has passed, the engine stops retrying and returns the semantic error
+// (ErrWouldBlock / ErrMore) together with the progress made so far.
+//
+// Yield always delegates to inner. A nil inner is treated as ReturnPolicy.
+func DeadlinePolicy(inner SemanticPolicy, deadline time.Time) SemanticPolicy {
+	if inner == nil {
+		inner = ReturnPolicy{}
+	}
+	return deadlinePolicy{forwardingPolicy: forwardingPolicy{inner}, deadline: deadline}
+}
+
+type deadlinePolicy struct {
+	forwardingPolicy
+	deadline time.Time
+}
+
+func (p deadlinePolicy) Yield(op Op) { p.SemanticPolicy.Yield(op) }
+
+func (p deadlinePolicy) OnWouldBlock(op Op) PolicyAction {
+	if time.Now().After(p.deadline) {
+		return PolicyReturn
+	}
+	return p.SemanticPolicy.OnWouldBlock(op)
+}
+
+func (p deadlinePolicy) OnMore(op Op) PolicyAction {
+	if time.Now().After(p.deadline) {
+		return PolicyReturn
+	}
+	return p.SemanticPolicy.OnMore(op)
+}
+
+// ContextPolicy returns a policy that defers to inner until ctx is done, and
+// returns PolicyReturn for every decision afterwards.
+//
+// Yield checks ctx before delegating; once cancellation is observed it is
+// remembered, inner.Yield is skipped, and the engine stops retrying. The
+// engine still reports ErrWouldBlock / ErrMore (the interface cannot carry
+// ctx.Err()), so callers should check ctx.Err() after it returns to tell a
+// cancellation from an ordinary stall. A nil inner is treated as ReturnPolicy.
+func ContextPolicy(ctx context.Context, inner SemanticPolicy) SemanticPolicy {
+	if inner == nil {
+		inner = ReturnPolicy{}
+	}
+	return &contextPolicy{ctx: ctx, forwardingPolicy: forwardingPolicy{inner}}
+}
+
+type contextPolicy struct {
+	ctx context.Context
+	forwardingPolicy
+	done atomic.Bool
+}
+
+func (p *contextPolicy) cancelled() bool {
+	if p.done.Load() {
+		return true
+	}
+	if p.ctx.Err() != nil {
+		p.done.Store(true)
+		return true
+	}
+	return false
+}
+
+func (p *contextPolicy) Yield(op Op) {
+	if p.cancelled() {
+		return
+	}
+	p.SemanticPolicy.Yield(op)
+}
+
+func (p *contextPolicy) OnWouldBlock(op Op) PolicyAction {
+	if p.cancelled() {
+		return PolicyReturn
+	}
+	return p.SemanticPolicy.OnWouldBlock(op)
+}
+
+func (p *contextPolicy) OnMore(op Op) PolicyAction {
+	if p.cancelled() {
+		return PolicyReturn
+	}
+	return p.SemanticPolicy.OnMore(op)
+}
+
+// CountingPolicy wraps Inner and counts its decisions and yields, giving
+// operators visibility into how often copies stall.
+//
+// All counters are updated atomically, so a CountingPolicy may be shared by
+// concurrent engines and read while they run. Use it by pointer. A nil Inner
+// is treated as ReturnPolicy.
+type CountingPolicy struct {
+	Inner SemanticPolicy
+
+	WouldBlockRetries atomic.Int64 // OnWouldBlock returned PolicyRetry
+	WouldBlockReturns atomic.Int64 // OnWouldBlock returned PolicyReturn
+	MoreRetries       atomic.Int64 // OnMore returned PolicyRetry
+	MoreReturns       atomic.Int64 // OnMore returned PolicyReturn
+	Yields            atomic.Int64 // Yield calls
+}
+
+// CountingSnapshot is a point-in-time copy of CountingPolicy's counters.
+type CountingSnapshot struct {
+	WouldBlockRetries int64
+	WouldBlockReturns int64
+	MoreRetries       int64
+	MoreReturns       int64
+	Yields            int64
+}
+
+func (p *CountingPolicy) inner() SemanticPolicy {
+	if p.Inner == nil {
+		return ReturnPolicy{}
+	}
+	return p.Inner
+}
+
+func (p *CountingPolicy) Yield(op Op) {
+	p.Yields.Add(1)
+	p.inner().Yield(op)
+}
+
+func (p *CountingPolicy) OnWouldBlock(op Op) PolicyAction {
+	a := p.inner().OnWouldBlock(op)
+	if a == PolicyRetry {
+		p.WouldBlockRetries.Add(1)
+	} else {
+		p.WouldBlockReturns.Add(1)
+	}
+	return a
+}
+
+func (p *CountingPolicy) OnMore(op Op) PolicyAction {
+	a := p.inner().OnMore(op)
+	if a == PolicyRetry {
+		p.MoreRetries.Add(1)
+	} else {
+		p.MoreReturns.Add(1)
+	}
+	return a
+}
+
+// Progress forwards to Inner if it is a ProgressReporter. It is not counted.
+func (p *CountingPolicy) Progress(op Op, n int) { forwardingPolicy{p.inner()}.Progress(op, n) }
+
+// OnNoProgress forwards to Inner if it is a NoProgressPolicy and retries
+// otherwise. It is not counted.
+func (p *CountingPolicy) OnNoProgress(op Op, consecutive int) PolicyAction {
+	return forwardingPolicy{p.inner()}.OnNoProgress(op, consecutive)
+}
+
+// Snapshot returns the current counter values. Each counter is read
+// atomically; the snapshot as a whole is not synchronized with in-flight
+// decisions.
+func (p *CountingPolicy) Snapshot() CountingSnapshot {
+	return CountingSnapshot{
+		WouldBlockRetries: p.WouldBlockRetries.Load(),
+		WouldBlockReturns: p.WouldBlockReturns.Load(),
+		MoreRetries:       p.MoreRetries.Load(),
+		MoreReturns:       p.MoreReturns.Load(),
+		Yields:            p.Yields.Load(),
+	}
+}
+
+// SlogPolicy returns a policy that delegates every decision to inner and
+// logs it to logger at slog.LevelDebug, so operators can see where copies
+// stall.
+//
+// Each OnWouldBlock / OnMore emits a record with the "op" (e.g., "CopyWrite")
+// and the "action" inner chose ("retry" or "return"); each Yield emits a
+// record with the "op". When debug is disabled on logger, records are not
+// built at all. A nil logger uses slog.Default(); a nil inner is treated as
+// ReturnPolicy.
+func SlogPolicy(logger *slog.Logger, inner SemanticPolicy) SemanticPolicy {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if inner == nil {
+		inner = ReturnPolicy{}
+	}
+	return slogPolicy{logger: logger, forwardingPolicy: forwardingPolicy{inner}}
+}
+
+type slogPolicy struct {
+	logger *slog.Logger
+	forwardingPolicy
+}
+
+func (p slogPolicy) Yield(op Op) {
+	if p.logger.Enabled(context.Background(), slog.LevelDebug) {
+		p.logger.LogAttrs(context.Background(), slog.LevelDebug, "iox: yield",
+			slog.String("op", op.String()))
+	}
+	p.SemanticPolicy.Yield(op)
+}
+
+func (p slogPolicy) OnWouldBlock(op Op) PolicyAction {
+	a := p.SemanticPolicy.OnWouldBlock(op)
+	p.log("iox: would block", op, a)
+	return a
+}
+
+func (p slogPolicy) OnMore(op Op) PolicyAction {
+	a := p.SemanticPolicy.OnMore(op)
+	p.log("iox: more", op, a)
+	return a
+}
+
+func (p slogPolicy) log(msg string, op Op, a PolicyAction) {
+	if !p.logger.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+	action := "return"
+	if a == PolicyRetry {
+		action = "retry"
+	}
+	p.logger.LogAttrs(context.Background(), slog.LevelDebug, msg,
+		slog.String("op", op.String()), slog.String("action", action))
+}
+
+// TracePolicy returns a policy that delegates every decision to inner and
+// reports it to sink before returning it, capturing the decision stream for
+// debugging. Each OnWouldBlock call is reported with kind OutcomeWouldBlock
+// and each OnMore call with OutcomeMore, together with the op and the action
+// inner chose. Yield is not reported.
+//
+// sink runs on the engine's goroutine, in decision order. A nil sink is
+// ignored; a nil inner is treated as ReturnPolicy.
+func TracePolicy(inner SemanticPolicy, sink func(op Op, kind Outcome, action PolicyAction)) SemanticPolicy {
+	if inner == nil {
+		inner = ReturnPolicy{}
+	}
+	return tracePolicy{forwardingPolicy: forwardingPolicy{inner}, sink: sink}
+}
+
+type tracePolicy struct {
+	forwardingPolicy
+	sink func(Op, Outcome, PolicyAction)
+}
+
+func (p tracePolicy) Yield(op Op) { p.SemanticPolicy.Yield(op) }
+
+func (p tracePolicy) OnWouldBlock(op Op) PolicyAction {
+	a := p.SemanticPolicy.OnWouldBlock(op)
+	if p.sink != nil {
+		p.sink(op, OutcomeWouldBlock, a)
+	}
+	return a
+}
+
+func (p tracePolicy) OnMore(op Op) PolicyAction {
+	a := p.SemanticPolicy.OnMore(op)
+	if p.sink != nil {
+		p.sink(op, OutcomeMore, a)
+	}
+	return a
+}
+
+// ChainPolicy composes policies into one: a retry happens only if every
+// policy agrees to retry.
+//
+// OnWouldBlock / OnMore consult the policies in order and short-circuit: the
+// first PolicyReturn is returned immediately and later policies are not
+// consulted for that decision. This matters for stateful policies (e.g., a
+// retry limiter only counts the decisions it actually sees), so put cheap or
+// terminal checks such as DeadlinePolicy first. OnNoProgress short-circuits
+// the same way over the policies that implement NoProgressPolicy. Yield and
+// Progress reach every policy in order.
+//
+// nil entries are ignored. A chain with no policies behaves like ReturnPolicy.
+func ChainPolicy(policies ...SemanticPolicy) SemanticPolicy {
+	chain := make(chainPolicy, 0, len(policies))
+	for _, p := range policies {
+		if p != nil {
+			chain = append(chain, p)
+		}
+	}
+	if len(chain) == 0 {
+		return ReturnPolicy{}
+	}
+	return chain
+}
+
+type chainPolicy []SemanticPolicy
+
+func (c chainPolicy) Yield(op Op) {
+	for _, p := range c {
+		p.Yield(op)
+	}
+}
+
+func (c chainPolicy) OnWouldBlock(op Op) PolicyAction {
+	for _, p := range c {
+		if p.OnWouldBlock(op) == PolicyReturn {
+			return PolicyReturn
+		}
+	}
+	return PolicyRetry
+}
+
+func (c chainPolicy) OnMore(op Op) PolicyAction {
+	for _, p := range c {
+		if p.OnMore(op) == PolicyReturn {
+			return PolicyReturn
+		}
+	}
+	return PolicyRetry
+}
+
+func (c chainPolicy) Progress(op Op, n int) {
+	for _, p := range c {
+		progress(p, op, n)
+	}
+}
+
+func (c chainPolicy) OnNoProgress(op Op, consecutive int) PolicyAction {
+	for _, p := range c {
+		if np, ok := p.(NoProgressPolicy); ok && np.OnNoProgress(op, consecutive) == PolicyReturn {
+			return PolicyReturn
+		}
+	}
+	return PolicyRetry
+}
@@ -6,9 +6,14 @@ package iox_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"io"
+	"log/slog"
+	"strings"
 	"testing"
+	"time"
 
 	"code.hybscloud.com/iox"
 )
@@ -632,6 +637,7 @@ func TestOpString_AllValuesAndUnknown(t *testing.T) {
 		{iox.OpTeeReaderSideWrite, "TeeReaderSideWrite"},
 		{iox.OpTeeWriterPrimaryWrite, "TeeWriterPrimaryWrite"},
 		{iox.OpTeeWriterTeeWrite, "TeeWriterTeeWrite"},
+		{iox.OpNone, "None"},
 		{iox.Op(255), "Op(unknown)"}, // default branch
 	}
 	for _, tc := range cases {
@@ -801,3 +807,673 @@ func TestTeeWriterPolicy_PrimaryWouldBlockOnceCompletes(t *testing.T) {
 		t.Fatalf("primary=%q tee=%q", p.buf.String(), tbuf.String())
 	}
 }
+
+// dataThenAlwaysWBReader returns its data once, then ErrWouldBlock forever.
+type dataThenAlwaysWBReader struct {
+	data []byte
+	done bool
+}
+
+func (r *dataThenAlwaysWBReader) Read(p []byte) (int, error) {
+	if !r.done {
+		r.done = true
+		return copy(p, r.data), nil
+	}
+	return 0, iox.ErrWouldBlock
+}
+
+func TestDeadlinePolicy_StopsRetryingAfterDeadline(t *testing.T) {
+	const window = 20 * time.Millisecond
+	src := &dataThenAlwaysWBReader{data: []byte("partial")}
+	var dst sliceWriter
+	start := time.Now()
+	pol := iox.DeadlinePolicy(iox.YieldPolicy{}, start.Add(window))
+	n, err := iox.CopyPolicy(&dst, src, pol)
+	elapsed := time.Since(start)
+	if !errors.Is(err, iox.ErrWouldBlock) {
+		t.Fatalf("want ErrWouldBlock got %v", err)
+	}
+	if n != 7 || string(dst.data) != "partial" {
+		t.Fatalf("n=%d dst=%q", n, string(dst.data))
+	}
+	if elapsed < window || elapsed > window+time.Second {
+		t.Fatalf("elapsed=%v, want within [%v, %v]", elapsed, window, window+time.Second)
+	}
+}
+
+func TestDeadlinePolicy_DelegatesBeforeDeadline(t *testing.T) {
+	var yields []iox.Op
+	inner := iox.PolicyFunc{
+		YieldFunc:      func(op iox.Op) { yields = append(yields, op) },
+		WouldBlockFunc: func(iox.Op) iox.PolicyAction { return iox.PolicyRetry },
+		MoreFunc:       func(iox.Op) iox.PolicyAction { return iox.PolicyRetry },
+	}
+	pol := iox.DeadlinePolicy(inner, time.Now().Add(time.Hour))
+	if pol.OnWouldBlock(iox.OpCopyRead) != iox.PolicyRetry || pol.OnMore(iox.OpCopyWrite) != iox.PolicyRetry {
+		t.Fatalf("expected inner decisions before deadline")
+	}
+	pol.Yield(iox.OpCopyRead)
+	if len(yields) != 1 || yields[0] != iox.OpCopyRead {
+		t.Fatalf("yields=%v", yields)
+	}
+	expired := iox.DeadlinePolicy(inner, time.Now().Add(-time.Second))
+	if expired.OnWouldBlock(iox.OpCopyRead) != iox.PolicyReturn || expired.OnMore(iox.OpCopyRead) != iox.PolicyReturn {
+		t.Fatalf("expected PolicyReturn after deadline")
+	}
+	if got := iox.DeadlinePolicy(nil, time.Now().Add(time.Hour)).OnWouldBlock(iox.OpCopyRead); got != iox.PolicyReturn {
+		t.Fatalf("nil inner: got %v", got)
+	}
+}
+
+// stallingReader returns ErrWouldBlock stalls times before each chunk, and
+// each chunk except the last with ErrMore.
+type stallingReader struct {
+	stalls int
+	chunks [][]byte
+	left   int
+	primed bool
+}
+
+func (r *stallingReader) Read(p []byte) (int, error) {
+	if !r.primed {
+		r.primed = true
+		r.left = r.stalls
+	}
+	if len(r.chunks) == 0 {
+		return 0, iox.EOF
+	}
+	if r.left > 0 {
+		r.left--
+		return 0, iox.ErrWouldBlock
+	}
+	r.left = r.stalls
+	n := copy(p, r.chunks[0])
+	r.chunks = r.chunks[1:]
+	if len(r.chunks) > 0 {
+		return n, iox.ErrMore
+	}
+	return n, nil
+}
+
+func TestCountingPolicy_CountsStalls(t *testing.T) {
+	pol := &iox.CountingPolicy{Inner: iox.YieldPolicy{YieldFunc: func(iox.Op) {}}}
+	src := &stallingReader{stalls: 3, chunks: [][]byte{[]byte("ab"), []byte("cd")}}
+	var dst sliceWriter
+	n, err := iox.CopyPolicy(&dst, src, pol)
+	if !errors.Is(err, iox.ErrMore) || n != 2 {
+		t.Fatalf("want (2, ErrMore) got (%d, %v)", n, err)
+	}
+	n, err = iox.CopyPolicy(&dst, src, pol)
+	if err != nil || n != 2 || string(dst.data) != "abcd" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, string(dst.data))
+	}
+	want := iox.CountingSnapshot{WouldBlockRetries: 6, MoreReturns: 1, Yields: 6}
+	if got := pol.Snapshot(); got != want {
+		t.Fatalf("snapshot=%+v want %+v", got, want)
+	}
+}
+
+func TestCountingPolicy_NilInnerReturns(t *testing.T) {
+	pol := &iox.CountingPolicy{}
+	n, err := iox.CopyPolicy(&sliceWriter{}, errReaderAlwaysWB{}, pol)
+	if !errors.Is(err, iox.ErrWouldBlock) || n != 0 {
+		t.Fatalf("want (0, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	_ = pol.OnMore(iox.OpCopyRead)
+	pol.Yield(iox.OpCopyRead)
+	want := iox.CountingSnapshot{WouldBlockReturns: 1, MoreReturns: 1, Yields: 1}
+	if got := pol.Snapshot(); got != want {
+		t.Fatalf("snapshot=%+v want %+v", got, want)
+	}
+	more := &iox.CountingPolicy{Inner: iox.PolicyFunc{MoreFunc: func(iox.Op) iox.PolicyAction { return iox.PolicyRetry }}}
+	_ = more.OnMore(iox.OpCopyWrite)
+	if more.MoreRetries.Load() != 1 {
+		t.Fatalf("MoreRetries=%d", more.MoreRetries.Load())
+	}
+}
+
+// captureHandler is a slog.Handler recording each record as
+// "msg key=value ...".
+type captureHandler struct {
+	level   slog.Level
+	records []string
+}
+
+func (h *captureHandler) Enabled(_ context.Context, l slog.Level) bool { return l >= h.level }
+
+func (h *captureHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	b.WriteString(r.Message)
+	r.Attrs(func(a slog.Attr) bool {
+		fmt.Fprintf(&b, " %s=%s", a.Key, a.Value)
+		return true
+	})
+	h.records = append(h.records, b.String())
+	return nil
+}
+
+func (h *captureHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *captureHandler) WithGroup(string) slog.Handler      { return h }
+
+func TestSlogPolicy_LogsStallsAndYields(t *testing.T) {
+	h := &captureHandler{level: slog.LevelDebug}
+	pol := iox.SlogPolicy(slog.New(h), iox.YieldPolicy{YieldFunc: func(iox.Op) {}})
+	src := &wbTwiceReader{data: []byte("hello")}
+	var dst sliceWriter
+	n, err := iox.CopyPolicy(&dst, src, pol)
+	if err != nil || n != 5 || string(dst.data) != "hello" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, string(dst.data))
+	}
+	want := []string{
+		"iox: would block op=CopyRead action=retry",
+		"iox: yield op=CopyRead",
+		"iox: would block op=CopyRead action=retry",
+		"iox: yield op=CopyRead",
+	}
+	if strings.Join(h.records, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("records=%q want %q", h.records, want)
+	}
+
+	h.records = nil
+	_ = pol.OnMore(iox.OpCopyWrite)
+	if len(h.records) != 1 || h.records[0] != "iox: more op=CopyWrite action=return" {
+		t.Fatalf("records=%q", h.records)
+	}
+}
+
+func TestSlogPolicy_DebugDisabled(t *testing.T) {
+	h := &captureHandler{level: slog.LevelInfo}
+	pol := iox.SlogPolicy(slog.New(h), nil)
+	n, err := iox.CopyPolicy(&sliceWriter{}, errReaderAlwaysWB{}, pol)
+	if !errors.Is(err, iox.ErrWouldBlock) || n != 0 {
+		t.Fatalf("want (0, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	pol.Yield(iox.OpCopyRead)
+	if len(h.records) != 0 {
+		t.Fatalf("records=%q", h.records)
+	}
+	if allocs := testing.AllocsPerRun(100, func() {
+		_ = pol.OnWouldBlock(iox.OpCopyRead)
+		pol.Yield(iox.OpCopyRead)
+	}); allocs != 0 {
+		t.Fatalf("allocs=%v with debug disabled", allocs)
+	}
+}
+
+// retryLimit returns a policy that retries the first n decisions of each kind
+// and returns afterwards.
+func retryLimit(n int) *iox.PolicyFunc {
+	wb, more := 0, 0
+	return &iox.PolicyFunc{
+		YieldFunc: func(iox.Op) {},
+		WouldBlockFunc: func(iox.Op) iox.PolicyAction {
+			wb++
+			if wb > n {
+				return iox.PolicyReturn
+			}
+			return iox.PolicyRetry
+		},
+		MoreFunc: func(iox.Op) iox.PolicyAction {
+			more++
+			if more > n {
+				return iox.PolicyReturn
+			}
+			return iox.PolicyRetry
+		},
+	}
+}
+
+func TestChainPolicy_StopsRetryingAtN(t *testing.T) {
+	const limit = 4
+	always := iox.PolicyFunc{
+		YieldFunc:      func(iox.Op) {},
+		WouldBlockFunc: func(iox.Op) iox.PolicyAction { return iox.PolicyRetry },
+		MoreFunc:       func(iox.Op) iox.PolicyAction { return iox.PolicyRetry },
+	}
+	counter := &iox.CountingPolicy{Inner: iox.ChainPolicy(always, retryLimit(limit))}
+	n, err := iox.CopyPolicy(&sliceWriter{}, errReaderAlwaysWB{}, counter)
+	if !errors.Is(err, iox.ErrWouldBlock) || n != 0 {
+		t.Fatalf("want (0, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	want := iox.CountingSnapshot{WouldBlockRetries: limit, WouldBlockReturns: 1, Yields: limit}
+	if got := counter.Snapshot(); got != want {
+		t.Fatalf("snapshot=%+v want %+v", got, want)
+	}
+}
+
+func TestChainPolicy_ShortCircuitAndYieldOrder(t *testing.T) {
+	var order []string
+	var consulted int
+	first := iox.PolicyFunc{
+		YieldFunc:      func(iox.Op) { order = append(order, "first") },
+		WouldBlockFunc: func(iox.Op) iox.PolicyAction { return iox.PolicyReturn },
+		MoreFunc:       func(iox.Op) iox.PolicyAction { return iox.PolicyRetry },
+	}
+	second := iox.PolicyFunc{
+		YieldFunc:      func(iox.Op) { order = append(order, "second") },
+		WouldBlockFunc: func(iox.Op) iox.PolicyAction { consulted++; return iox.PolicyRetry },
+		MoreFunc:       func(iox.Op) iox.PolicyAction { consulted++; return iox.PolicyRetry },
+	}
+	chain := iox.ChainPolicy(first, nil, second)
+	if chain.OnWouldBlock(iox.OpCopyRead) != iox.PolicyReturn || consulted != 0 {
+		t.Fatalf("expected short-circuit on first PolicyReturn, consulted=%d", consulted)
+	}
+	if chain.OnMore(iox.OpCopyRead) != iox.PolicyRetry || consulted != 1 {
+		t.Fatalf("expected retry when all agree, consulted=%d", consulted)
+	}
+	chain.Yield(iox.OpCopyRead)
+	if len(order) != 2 || order[0] != "first" || order[1] != "second" {
+		t.Fatalf("yield order=%v", order)
+	}
+	empty := iox.ChainPolicy()
+	if empty.OnWouldBlock(iox.OpCopyRead) != iox.PolicyReturn || empty.OnMore(iox.OpCopyRead) != iox.PolicyReturn {
+		t.Fatal("empty chain should return")
+	}
+}
+
+func TestContextPolicy_StopsRetriesOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	yields := 0
+	inner := &iox.PolicyFunc{
+		YieldFunc: func(iox.Op) {
+			yields++
+			if yields == 3 {
+				cancel()
+			}
+		},
+		WouldBlockFunc: func(iox.Op) iox.PolicyAction { return iox.PolicyRetry },
+	}
+	var side bytes.Buffer
+	tr := iox.TeeReaderPolicy(errReaderAlwaysWB{}, &side, iox.ContextPolicy(ctx, inner))
+	n, err := tr.Read(make([]byte, 8))
+	if n != 0 || !errors.Is(err, iox.ErrWouldBlock) {
+		t.Fatalf("want (0, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	if yields != 3 || ctx.Err() == nil {
+		t.Fatalf("yields=%d ctx.Err()=%v", yields, ctx.Err())
+	}
+}
+
+func TestContextPolicy_AlreadyCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p := iox.ContextPolicy(ctx, iox.YieldPolicy{})
+	if p.OnWouldBlock(iox.OpCopyRead) != iox.PolicyReturn || p.OnMore(iox.OpCopyRead) != iox.PolicyReturn {
+		t.Fatal("cancelled context must return")
+	}
+	n, err := iox.CopyPolicy(&bytes.Buffer{}, errReaderAlwaysWB{}, p)
+	if n != 0 || !errors.Is(err, iox.ErrWouldBlock) {
+		t.Fatalf("CopyPolicy=(%d,%v)", n, err)
+	}
+
+	// Live context defers to inner; nil inner means ReturnPolicy.
+	live := iox.ContextPolicy(context.Background(), nil)
+	if live.OnWouldBlock(iox.OpCopyRead) != iox.PolicyReturn {
+		t.Fatal("nil inner must behave as ReturnPolicy")
+	}
+	if iox.ContextPolicy(context.Background(), iox.YieldPolicy{}).OnWouldBlock(iox.OpCopyRead) != iox.PolicyRetry {
+		t.Fatal("live context must defer to inner")
+	}
+}
+
+// noProgressCap retries every semantic error but gives up after max
+// consecutive zero-progress retries, recording the counts it was shown.
+type noProgressCap struct {
+	iox.YieldPolicy
+	max    int
+	counts []int
+}
+
+func (p *noProgressCap) OnNoProgress(op iox.Op, consecutive int) iox.PolicyAction {
+	p.counts = append(p.counts, consecutive)
+	if consecutive >= p.max {
+		return iox.PolicyReturn
+	}
+	return iox.PolicyRetry
+}
+
+func TestNoProgressPolicy_CapsZeroProgressRetries(t *testing.T) {
+	p := &noProgressCap{max: 5}
+	n, err := iox.CopyPolicy(&bytes.Buffer{}, errReaderAlwaysWB{}, p)
+	if n != 0 || err != iox.ErrWouldBlock {
+		t.Fatalf("want (0, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	want := []int{1, 2, 3, 4, 5}
+	if len(p.counts) != len(want) {
+		t.Fatalf("counts=%v want %v", p.counts, want)
+	}
+	for i := range want {
+		if p.counts[i] != want[i] {
+			t.Fatalf("counts=%v want %v", p.counts, want)
+		}
+	}
+}
+
+func TestNoProgressPolicy_ProgressResetsCount(t *testing.T) {
+	// Two would-blocks, data, two would-blocks, EOF: never 3 in a row.
+	src := &scriptedReader{steps: []struct {
+		b   []byte
+		err error
+	}{
+		{err: iox.ErrWouldBlock}, {err: iox.ErrWouldBlock}, {b: []byte("ab")},
+		{err: iox.ErrWouldBlock}, {err: iox.ErrWouldBlock}, {b: []byte("cd")},
+	}}
+	p := &noProgressCap{max: 3}
+	var dst sliceWriter // no ReaderFrom: exercise the read/write loop
+	n, err := iox.CopyPolicy(&dst, src, p)
+	if n != 4 || err != nil || string(dst.data) != "abcd" {
+		t.Fatalf("(%d,%v,%q)", n, err, dst.data)
+	}
+	if len(p.counts) != 4 || p.counts[2] != 1 {
+		t.Fatalf("counts=%v want [1 2 1 2]", p.counts)
+	}
+
+	// Policies without the hook keep retrying as before.
+	if n, err := iox.CopyPolicy(&bytes.Buffer{}, &wbThenDataReader{data: []byte("x")}, iox.YieldPolicy{}); n != 1 || err != nil {
+		t.Fatalf("plain policy: (%d,%v)", n, err)
+	}
+}
+
+// policyWrappers are the wrapping policies that must keep the optional
+// ProgressReporter and NoProgressPolicy extensions of the wrapped policy.
+var policyWrappers = []struct {
+	name string
+	wrap func(iox.SemanticPolicy) iox.SemanticPolicy
+}{
+	{"SlogPolicy", func(p iox.SemanticPolicy) iox.SemanticPolicy {
+		return iox.SlogPolicy(slog.New(&captureHandler{level: slog.LevelInfo}), p)
+	}},
+	{"TracePolicy", func(p iox.SemanticPolicy) iox.SemanticPolicy { return iox.TracePolicy(p, nil) }},
+	{"DeadlinePolicy", func(p iox.SemanticPolicy) iox.SemanticPolicy {
+		return iox.DeadlinePolicy(p, time.Now().Add(time.Hour))
+	}},
+	{"ContextPolicy", func(p iox.SemanticPolicy) iox.SemanticPolicy {
+		return iox.ContextPolicy(context.Background(), p)
+	}},
+	{"CountingPolicy", func(p iox.SemanticPolicy) iox.SemanticPolicy { return &iox.CountingPolicy{Inner: p} }},
+	{"ChainPolicy", func(p iox.SemanticPolicy) iox.SemanticPolicy { return iox.ChainPolicy(iox.YieldPolicy{}, p) }},
+}
+
+func TestNoProgressPolicy_SeenThroughWrappers(t *testing.T) {
+	for _, w := range policyWrappers {
+		p := &noProgressCap{max: 2}
+		n, err := iox.CopyPolicy(&sliceWriter{}, errReaderAlwaysWB{}, w.wrap(p))
+		if n != 0 || err != iox.ErrWouldBlock || len(p.counts) != 2 {
+			t.Fatalf("%s: (%d,%v) counts=%v", w.name, n, err, p.counts)
+		}
+	}
+
+	p := &noProgressCap{max: 2}
+	n, more, err := iox.CopyPolicyCounts(&sliceWriter{}, errReaderAlwaysWB{}, p)
+	if n != 0 || more != 0 || err != iox.ErrWouldBlock || len(p.counts) != 2 {
+		t.Fatalf("CopyPolicyCounts: (%d,%d,%v) counts=%v", n, more, err, p.counts)
+	}
+}
+
+func TestAdaptivePolicy_EscalatesToBackoff(t *testing.T) {
+	const spins = 3
+	p := iox.AdaptivePolicy(spins)
+	sleeps := 0
+	p.SetSleepFunc(func(time.Duration) { sleeps++ })
+
+	if p.OnWouldBlock(iox.OpCopyRead) != iox.PolicyRetry || p.OnMore(iox.OpCopyRead) != iox.PolicyReturn {
+		t.Fatal("unexpected decisions")
+	}
+	for i := 1; i <= spins; i++ {
+		p.Yield(iox.OpCopyRead)
+		if sleeps != 0 {
+			t.Fatalf("yield %d slept", i)
+		}
+	}
+	p.Yield(iox.OpCopyRead)
+	p.Yield(iox.OpCopyRead)
+	if sleeps != 2 {
+		t.Fatalf("sleeps=%d want 2 after spins exhausted", sleeps)
+	}
+
+	// Escalation is per Op.
+	p.Yield(iox.OpCopyWrite)
+	if sleeps != 2 {
+		t.Fatal("other Op should still spin")
+	}
+
+	// Progress (OnMore) restarts the escalation for that Op.
+	p.OnMore(iox.OpCopyRead)
+	for i := 0; i < spins; i++ {
+		p.Yield(iox.OpCopyRead)
+	}
+	if sleeps != 2 {
+		t.Fatalf("sleeps=%d after reset, want 2", sleeps)
+	}
+	// OpCopyWrite has spun once; spin it to the limit, then Reset.
+	p.Yield(iox.OpCopyWrite)
+	p.Yield(iox.OpCopyWrite)
+	p.Reset()
+	for i := 0; i < spins; i++ {
+		p.Yield(iox.OpCopyWrite)
+	}
+	if sleeps != 2 {
+		t.Fatal("Reset did not restart escalation")
+	}
+	p.Yield(iox.OpCopyWrite)
+	if sleeps != 3 {
+		t.Fatalf("sleeps=%d want 3", sleeps)
+	}
+}
+
+func TestAdaptivePolicy_DrivesCopy(t *testing.T) {
+	p := iox.AdaptivePolicy(1)
+	sleeps := 0
+	p.SetSleepFunc(func(time.Duration) { sleeps++ })
+	src := &wbTwiceReader{data: []byte("data")}
+	var dst sliceWriter
+	n, err := iox.CopyPolicy(&dst, src, p)
+	if n != 4 || err != nil || string(dst.data) != "data" {
+		t.Fatalf("(%d,%v,%q)", n, err, dst.data)
+	}
+	if sleeps != 1 {
+		t.Fatalf("sleeps=%d want 1 (one spin, then backoff)", sleeps)
+	}
+}
+
+// progressRecorder is a retrying policy recording ProgressReporter calls.
+type progressRecorder struct {
+	iox.YieldPolicy
+	steps []string
+}
+
+func (p *progressRecorder) OnWouldBlock(iox.Op) iox.PolicyAction { return iox.PolicyRetry }
+
+func (p *progressRecorder) Progress(op iox.Op, n int) {
+	p.steps = append(p.steps, fmt.Sprintf("%s:%d", op, n))
+}
+
+func TestProgressReporter_CopyPolicy(t *testing.T) {
+	pol := &progressRecorder{}
+	dst := &partialThenWBWriter{k: 2}
+	n, err := iox.CopyPolicy(dst, &plainReader{data: []byte("abcdef")}, pol)
+	if n != 6 || err != nil || dst.buf.String() != "abcdef" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, dst.buf.String())
+	}
+	want := "[CopyRead:6 CopyWrite:2 CopyWrite:4 CopyRead:0]"
+	if got := fmt.Sprint(pol.steps); got != want {
+		t.Fatalf("steps=%s want %s", got, want)
+	}
+}
+
+func TestProgressReporter_SeenThroughWrappers(t *testing.T) {
+	want := "[CopyRead:6 CopyWrite:2 CopyWrite:4 CopyRead:0]"
+	for _, w := range policyWrappers {
+		pol := &progressRecorder{}
+		dst := &partialThenWBWriter{k: 2}
+		n, err := iox.CopyPolicy(dst, &plainReader{data: []byte("abcdef")}, w.wrap(pol))
+		if n != 6 || err != nil {
+			t.Fatalf("%s: n=%d err=%v", w.name, n, err)
+		}
+		if got := fmt.Sprint(pol.steps); got != want {
+			t.Fatalf("%s: steps=%s want %s", w.name, got, want)
+		}
+	}
+}
+
+func TestProgressReporter_TeeWriter(t *testing.T) {
+	pol := &progressRecorder{}
+	primary := &partialThenWBWriter{k: 2}
+	var side bytes.Buffer
+	w := iox.TeeWriterPolicy(primary, &side, pol)
+	if n, err := w.Write([]byte("abcdef")); n != 6 || err != nil {
+		t.Fatalf("n=%d err=%v", n, err)
+	}
+	want := "[TeeWriterPrimaryWrite:2 TeeWriterTeeWrite:2 TeeWriterPrimaryWrite:4 TeeWriterTeeWrite:4]"
+	if got := fmt.Sprint(pol.steps); got != want {
+		t.Fatalf("steps=%s want %s", got, want)
+	}
+}
+
+func TestAdaptivePolicy_ProgressResets(t *testing.T) {
+	p := iox.AdaptivePolicy(1)
+	sleeps := 0
+	p.SetSleepFunc(func(time.Duration) { sleeps++ })
+	p.Yield(iox.OpCopyWrite)
+	p.Progress(iox.OpCopyWrite, 0) // no bytes moved: escalation continues
+	p.Yield(iox.OpCopyWrite)
+	if sleeps != 1 {
+		t.Fatalf("sleeps=%d want 1", sleeps)
+	}
+	p.Progress(iox.OpCopyWrite, 3)
+	p.Yield(iox.OpCopyWrite)
+	if sleeps != 1 {
+		t.Fatalf("sleeps=%d after progress, want 1 (spinning again)", sleeps)
+	}
+}
+
+func TestPerOpBackoffPolicy_IndependentCurves(t *testing.T) {
+	p := iox.PerOpBackoffPolicy()
+	var blocks []int
+	p.SetSleepFunc(func(d time.Duration) {
+		// Round away the ±12.5% jitter to recover the block number.
+		blocks = append(blocks, int((d+iox.DefaultBackoffBase/2)/iox.DefaultBackoffBase))
+	})
+	if p.OnWouldBlock(iox.OpCopyRead) != iox.PolicyRetry {
+		t.Fatal("OnWouldBlock should retry")
+	}
+	for _, op := range []iox.Op{
+		iox.OpCopyRead, iox.OpCopyRead, iox.OpCopyWrite, iox.OpCopyRead,
+		iox.OpCopyWrite, iox.OpCopyRead, iox.OpCopyWrite,
+	} {
+		p.Yield(op)
+	}
+	// Read: blocks 1,2,2,3. Write: blocks 1,2,2.
+	want := []int{1, 2, 1, 2, 2, 3, 2}
+	if fmt.Sprint(blocks) != fmt.Sprint(want) {
+		t.Fatalf("blocks=%v want %v", blocks, want)
+	}
+
+	blocks = nil
+	p.Progress(iox.OpCopyWrite, 1)
+	p.Yield(iox.OpCopyWrite)
+	p.Yield(iox.OpCopyRead)
+	if fmt.Sprint(blocks) != fmt.Sprint([]int{1, 3}) {
+		t.Fatalf("after write progress blocks=%v want [1 3]", blocks)
+	}
+}
+
+func TestTracePolicy_RecordsDecisions(t *testing.T) {
+	mixed := iox.PolicyFunc{
+		YieldFunc: func(iox.Op) {},
+		WouldBlockFunc: func(op iox.Op) iox.PolicyAction {
+			if op == iox.OpCopyRead {
+				return iox.PolicyRetry
+			}
+			return iox.PolicyReturn
+		},
+	}
+	type decision struct {
+		op     iox.Op
+		kind   iox.Outcome
+		action iox.PolicyAction
+	}
+	var got []decision
+	pol := iox.TracePolicy(mixed, func(op iox.Op, kind iox.Outcome, a iox.PolicyAction) {
+		got = append(got, decision{op, kind, a})
+	})
+	src := iox.NewRewindReader(&scriptedReader{steps: []scriptStep{{err: iox.ErrWouldBlock}, {b: []byte("abcd")}}})
+	n, err := iox.CopyPolicy(&gateWriter{}, src, pol)
+	if n != 0 || !errors.Is(err, iox.ErrWouldBlock) {
+		t.Fatalf("want (0, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	_ = pol.OnMore(iox.OpCopyWrite)
+	want := []decision{
+		{iox.OpCopyRead, iox.OutcomeWouldBlock, iox.PolicyRetry},
+		{iox.OpCopyWrite, iox.OutcomeWouldBlock, iox.PolicyReturn},
+		{iox.OpCopyWrite, iox.OutcomeMore, iox.PolicyReturn},
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("decisions=%v want %v", got, want)
+	}
+}
+
+// gateReader returns ErrWouldBlock until ready is set, then serves data.
+type gateReader struct {
+	ready bool
+	data  []byte
+}
+
+func (r *gateReader) Read(p []byte) (int, error) {
+	if !r.ready {
+		return 0, iox.ErrWouldBlock
+	}
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestNotifyPolicy_WaitsForReadiness(t *testing.T) {
+	src := &gateReader{data: []byte("event")}
+	var waits []iox.Op
+	pol := iox.NotifyPolicy{WaitReady: func(op iox.Op) {
+		waits = append(waits, op)
+		src.ready = true // the event loop reports readiness
+	}}
+	var dst sliceWriter
+	n, err := iox.CopyPolicy(&dst, src, pol)
+	if n != 5 || err != nil || string(dst.data) != "event" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, dst.data)
+	}
+	if len(waits) != 1 || waits[0] != iox.OpCopyRead {
+		t.Fatalf("waits=%v want exactly one CopyRead wait", waits)
+	}
+	if pol.OnMore(iox.OpCopyRead) != iox.PolicyReturn {
+		t.Fatal("OnMore should return by default")
+	}
+	if (iox.NotifyPolicy{RetryMore: true}).OnMore(iox.OpCopyRead) != iox.PolicyRetry {
+		t.Fatal("RetryMore should retry")
+	}
+}
+
+func TestSchedulerPolicy_ParkResumesWhenWritable(t *testing.T) {
+	dst := &gateWriter{}
+	var parks []iox.Op
+	pol := iox.SchedulerPolicy(func(op iox.Op) {
+		parks = append(parks, op)
+		dst.quota = 3 // the scheduler resumes the fiber once the sink drains
+	})
+	n, err := iox.CopyPolicy(dst, &plainReader{data: []byte("abcdefg")}, pol)
+	if n != 7 || err != nil || dst.buf.String() != "abcdefg" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, dst.buf.String())
+	}
+	if len(parks) != 3 {
+		t.Fatalf("parks=%v want 3", parks)
+	}
+	for _, op := range parks {
+		if op != iox.OpCopyWrite {
+			t.Fatalf("parked on %v, want CopyWrite", op)
+		}
+	}
+	if pol.OnMore(iox.OpCopyWrite) != iox.PolicyReturn {
+		t.Fatal("OnMore should return")
+	}
+}
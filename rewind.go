@@ -0,0 +1,94 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iox
+
+import "io"
+
+// DefaultRewindWindow is the rewind window used by NewRewindReader. It equals
+// the size of Copy's staging Buffer, so a rollback of any partial write made
+// by Copy's read/write loop always fits.
+const DefaultRewindWindow = len(Buffer{})
+
+// RewindReader wraps a non-seekable Reader (e.g., a socket) and records the
+// most recently consumed bytes so that Seek can move backward within a
+// bounded window. Bytes rewound over are delivered again by subsequent Reads
+// before anything new is read from the underlying reader.
+//
+// This lets the Seeker rollback in Copy and CopyPolicy recover from partial
+// semantic writes instead of failing with ErrNoSeeker.
+//
+// Memory cost: the reader keeps up to window bytes of history. Each Read that
+// overflows the window shifts the history, costing O(window) copying.
+//
+// RewindReader does not implement WriterTo, so Copy always uses its
+// read/write loop (where rollback applies). It is not safe for concurrent use.
+type RewindReader struct {
+	r      Reader
+	hist   []byte // last consumed bytes, oldest first
+	window int
+	back   int   // bytes rewound, re-served from the tail of hist
+	total  int64 // bytes read from r
+}
+
+// NewRewindReader returns a RewindReader over r with DefaultRewindWindow.
+func NewRewindReader(r Reader) *RewindReader { return NewRewindReaderSize(r, DefaultRewindWindow) }
+
+// NewRewindReaderSize returns a RewindReader over r that can rewind at most
+// window bytes. A window <= 0 selects DefaultRewindWindow. When used with
+// CopyBuffer, window must be at least len(buf).
+func NewRewindReaderSize(r Reader, window int) *RewindReader {
+	if window <= 0 {
+		window = DefaultRewindWindow
+	}
+	return &RewindReader{r: r, window: window}
+}
+
+// Read delivers rewound bytes first, then reads from the underlying reader.
+// Errors from the underlying reader, including ErrWouldBlock and ErrMore, are
+// returned unchanged.
+func (rr *RewindReader) Read(p []byte) (n int, err error) {
+	if rr.back > 0 {
+		n = copy(p, rr.hist[len(rr.hist)-rr.back:])
+		rr.back -= n
+		return n, nil
+	}
+	n, err = rr.r.Read(p)
+	if n > 0 {
+		rr.record(p[:n])
+	}
+	return n, err
+}
+
+func (rr *RewindReader) record(b []byte) {
+	rr.total += int64(len(b))
+	if len(b) >= rr.window {
+		rr.hist = append(rr.hist[:0], b[len(b)-rr.window:]...)
+		return
+	}
+	if drop := len(rr.hist) + len(b) - rr.window; drop > 0 {
+		rr.hist = rr.hist[:copy(rr.hist, rr.hist[drop:])]
+	}
+	rr.hist = append(rr.hist, b...)
+}
+
+// Seek sets the position for the next Read. Only positions within the
+// recorded window are reachable: from the oldest retained byte up to the
+// furthest byte read so far. whence may be io.SeekStart or io.SeekCurrent;
+// io.SeekEnd is not supported.
+func (rr *RewindReader) Seek(offset int64, whence int) (int64, error) {
+	pos := rr.total - int64(rr.back)
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += pos
+	default:
+		return pos, errWhence
+	}
+	if offset < rr.total-int64(len(rr.hist)) || offset > rr.total {
+		return pos, errOffset
+	}
+	rr.back = int(rr.total - offset)
+	return offset, nil
+}
@@ -0,0 +1,69 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iox_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"code.hybscloud.com/iox"
+)
+
+func TestRewindReader_CopyWithPartialWrites(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 5000) // spans several Copy chunks
+	src := iox.NewRewindReader(&plainReader{data: data})
+	cw := &cappedWriter{limit: 7000}
+	dst := iox.ShortWriteAsWouldBlock(cw)
+
+	var total int64
+	for i := 0; ; i++ {
+		n, err := iox.Copy(dst, src)
+		total += n
+		if err == nil {
+			break
+		}
+		if err != iox.ErrWouldBlock {
+			t.Fatalf("round %d: err=%v", i, err)
+		}
+		if i > len(data) {
+			t.Fatal("no progress")
+		}
+	}
+	if total != int64(len(data)) || !bytes.Equal(cw.buf.Bytes(), data) {
+		t.Fatalf("total=%d match=%v", total, bytes.Equal(cw.buf.Bytes(), data))
+	}
+}
+
+func TestRewindReader_SeekWindow(t *testing.T) {
+	rr := iox.NewRewindReaderSize(&plainReader{data: []byte("abcdefghij")}, 4)
+	p := make([]byte, 6)
+	if n, _ := rr.Read(p); n != 6 {
+		t.Fatalf("n=%d", n)
+	}
+	// Window holds "cdef": positions 2..6.
+	if _, err := rr.Seek(-5, io.SeekCurrent); err == nil {
+		t.Fatal("seek beyond window accepted")
+	}
+	if _, err := rr.Seek(1, io.SeekCurrent); err == nil {
+		t.Fatal("seek past furthest read accepted")
+	}
+	if _, err := rr.Seek(0, io.SeekEnd); err == nil {
+		t.Fatal("SeekEnd accepted")
+	}
+	if pos, err := rr.Seek(-3, io.SeekCurrent); pos != 3 || err != nil {
+		t.Fatalf("Seek(-3)=(%d,%v)", pos, err)
+	}
+	rest, err := io.ReadAll(rr)
+	if err != nil || string(rest) != "defghij" {
+		t.Fatalf("rest=%q err=%v", rest, err)
+	}
+	if pos, err := rr.Seek(8, io.SeekStart); pos != 8 || err != nil {
+		t.Fatalf("Seek(8, start)=(%d,%v)", pos, err)
+	}
+	if n, _ := rr.Read(p); string(p[:n]) != "ij" {
+		t.Fatalf("replay=%q", p[:n])
+	}
+}
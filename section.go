@@ -0,0 +1,212 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iox
+
+import (
+	"errors"
+	"io"
+)
+
+var (
+	errWhence = errors.New("Seek: invalid whence")
+	errOffset = errors.New("Seek: invalid offset")
+
+	errNegativeOffset = errors.New("ReadAt: negative offset")
+)
+
+// SectionReader implements Read, Seek, and ReadAt on a section of an
+// underlying ReaderAt, analogous to io.SectionReader.
+//
+// Unlike io.SectionReader it also implements WriterTo, so Copy uses the fast
+// path, and it preserves iox semantics: ErrWouldBlock / ErrMore returned by
+// the underlying ReadAt (e.g., asynchronous storage) are propagated unchanged
+// with any partial data. The read offset only advances by the bytes actually
+// delivered, so a semantic stop never skips data.
+type SectionReader struct {
+	r     ReaderAt
+	base  int64
+	off   int64
+	limit int64
+}
+
+// NewSectionReader returns a SectionReader that reads from r starting at
+// offset off and stops with EOF after n bytes.
+func NewSectionReader(r ReaderAt, off int64, n int64) *SectionReader {
+	var remaining int64
+	const maxint64 = 1<<63 - 1
+	if off <= maxint64-n {
+		remaining = n + off
+	} else {
+		// Overflow: treat the section as extending to the end of the file.
+		remaining = maxint64
+	}
+	return &SectionReader{r: r, base: off, off: off, limit: remaining}
+}
+
+// Size returns the size of the section in bytes.
+func (s *SectionReader) Size() int64 { return s.limit - s.base }
+
+// Read reads up to len(p) bytes from the current offset of the section.
+func (s *SectionReader) Read(p []byte) (n int, err error) {
+	if s.off >= s.limit {
+		return 0, io.EOF
+	}
+	if max := s.limit - s.off; int64(len(p)) > max {
+		p = p[0:max]
+	}
+	n, err = s.r.ReadAt(p, s.off)
+	s.off += int64(n)
+	return n, err
+}
+
+// Seek sets the offset for the next Read, relative to the section.
+func (s *SectionReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	default:
+		return 0, errWhence
+	case io.SeekStart:
+		offset += s.base
+	case io.SeekCurrent:
+		offset += s.off
+	case io.SeekEnd:
+		offset += s.limit
+	}
+	if offset < s.base {
+		return 0, errOffset
+	}
+	s.off = offset
+	return offset - s.base, nil
+}
+
+// ReadAt reads len(p) bytes at offset off relative to the section. It does
+// not affect the Read/Seek offset.
+func (s *SectionReader) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 || off >= s.Size() {
+		return 0, io.EOF
+	}
+	off += s.base
+	if max := s.limit - off; int64(len(p)) > max {
+		p = p[0:max]
+		n, err = s.r.ReadAt(p, off)
+		if err == nil {
+			err = io.EOF
+		}
+		return n, err
+	}
+	return s.r.ReadAt(p, off)
+}
+
+// WriteTo writes the rest of the section to w, starting at the current
+// offset. io.EOF maps to nil.
+//
+// Semantic errors from either ReadAt or w.Write are returned unchanged with
+// the number of bytes written so far; the offset reflects only bytes accepted
+// by w, so calling WriteTo again resumes without loss.
+func (s *SectionReader) WriteTo(w Writer) (written int64, err error) {
+	var buf Buffer
+	for s.off < s.limit {
+		p := buf[:]
+		if max := s.limit - s.off; int64(len(p)) > max {
+			p = p[:max]
+		}
+		nr, er := s.r.ReadAt(p, s.off)
+		if nr > 0 {
+			nw, ew := w.Write(p[:nr])
+			if nw > 0 {
+				s.off += int64(nw)
+				written += int64(nw)
+			}
+			if ew != nil {
+				return written, ew
+			}
+			if nw != nr {
+				return written, io.ErrShortWrite
+			}
+		}
+		if er != nil {
+			if er == io.EOF {
+				return written, nil
+			}
+			return written, er
+		}
+		if nr == 0 {
+			return written, nil
+		}
+	}
+	return written, nil
+}
+
+// BufferedReaderAt adapts a forward-only Reader to ReaderAt by caching every
+// byte read from it. Offsets already seen are served from the cache; offsets
+// ahead are reached by reading forward on demand.
+//
+// The cache grows to the furthest offset requested and is never released, so
+// memory use equals the number of bytes consumed from the underlying reader.
+//
+// iox semantics: if the underlying reader returns ErrWouldBlock (or a (0, nil)
+// read) before the requested range is available, ReadAt returns the bytes it
+// has at off together with ErrWouldBlock; retry once more data has arrived.
+// ErrMore with data keeps reading. EOF and failures are sticky.
+//
+// A BufferedReaderAt is not safe for concurrent use.
+type BufferedReaderAt struct {
+	r   Reader
+	buf []byte
+	err error // sticky EOF or failure from r
+}
+
+// NewBufferedReaderAt returns a BufferedReaderAt reading from r.
+func NewBufferedReaderAt(r Reader) *BufferedReaderAt { return &BufferedReaderAt{r: r} }
+
+// Buffered returns the number of bytes cached so far.
+func (b *BufferedReaderAt) Buffered() int { return len(b.buf) }
+
+// ReadAt reads len(p) bytes starting at off, reading forward from the
+// underlying reader as needed.
+func (b *BufferedReaderAt) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errNegativeOffset
+	}
+	need := off + int64(len(p))
+	for int64(len(b.buf)) < need && b.err == nil {
+		if !b.fill() {
+			break
+		}
+	}
+	if off < int64(len(b.buf)) {
+		n = copy(p, b.buf[off:])
+	}
+	if n < len(p) {
+		if b.err != nil {
+			return n, b.err
+		}
+		return n, ErrWouldBlock
+	}
+	return n, nil
+}
+
+// fill performs one read into the cache and reports whether more may be
+// available right now.
+func (b *BufferedReaderAt) fill() bool {
+	const minRead = 512
+	if cap(b.buf)-len(b.buf) < minRead {
+		nb := make([]byte, len(b.buf), 2*cap(b.buf)+minRead)
+		copy(nb, b.buf)
+		b.buf = nb
+	}
+	n, err := b.r.Read(b.buf[len(b.buf):cap(b.buf)])
+	b.buf = b.buf[:len(b.buf)+n]
+	switch {
+	case err == nil:
+		return n > 0
+	case IsMore(err):
+		return true
+	case IsWouldBlock(err):
+		return false
+	default:
+		b.err = err
+		return false
+	}
+}
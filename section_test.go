@@ -0,0 +1,204 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iox_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"code.hybscloud.com/iox"
+)
+
+// -----------------------------------------------------------------------------
+// SectionReader tests
+// -----------------------------------------------------------------------------
+
+// wbOnceReaderAt returns ErrWouldBlock on the first ReadAt call, then serves
+// data from the underlying bytes.
+type wbOnceReaderAt struct {
+	data    []byte
+	blocked bool
+}
+
+func (r *wbOnceReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if !r.blocked {
+		r.blocked = true
+		return 0, iox.ErrWouldBlock
+	}
+	if off >= int64(len(r.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, r.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func TestSectionReader_PartialReads(t *testing.T) {
+	sr := iox.NewSectionReader(bytes.NewReader([]byte("0123456789")), 2, 5)
+	if sr.Size() != 5 {
+		t.Fatalf("Size()=%d", sr.Size())
+	}
+	var b [3]byte
+	n, err := sr.Read(b[:])
+	if err != nil || string(b[:n]) != "234" {
+		t.Fatalf("n=%d err=%v data=%q", n, err, string(b[:n]))
+	}
+	n, err = sr.Read(b[:])
+	if err != nil || string(b[:n]) != "56" {
+		t.Fatalf("n=%d err=%v data=%q", n, err, string(b[:n]))
+	}
+	if n, err = sr.Read(b[:]); err != io.EOF || n != 0 {
+		t.Fatalf("want (0, EOF) got (%d, %v)", n, err)
+	}
+}
+
+func TestSectionReader_SeekWithinSection(t *testing.T) {
+	sr := iox.NewSectionReader(bytes.NewReader([]byte("0123456789")), 2, 5)
+	if pos, err := sr.Seek(-2, io.SeekEnd); err != nil || pos != 3 {
+		t.Fatalf("pos=%d err=%v", pos, err)
+	}
+	var b [4]byte
+	if n, _ := sr.Read(b[:]); string(b[:n]) != "56" {
+		t.Fatalf("after SeekEnd read %q", string(b[:n]))
+	}
+	if pos, err := sr.Seek(1, io.SeekStart); err != nil || pos != 1 {
+		t.Fatalf("pos=%d err=%v", pos, err)
+	}
+	if pos, err := sr.Seek(1, io.SeekCurrent); err != nil || pos != 2 {
+		t.Fatalf("pos=%d err=%v", pos, err)
+	}
+	if n, _ := sr.Read(b[:2]); string(b[:n]) != "45" {
+		t.Fatalf("after SeekCurrent read %q", string(b[:n]))
+	}
+	if _, err := sr.Seek(-1, io.SeekStart); err == nil {
+		t.Fatal("expected error seeking before section start")
+	}
+	if _, err := sr.Seek(0, 42); err == nil {
+		t.Fatal("expected error for invalid whence")
+	}
+}
+
+func TestSectionReader_ReadAt(t *testing.T) {
+	sr := iox.NewSectionReader(bytes.NewReader([]byte("0123456789")), 2, 5)
+	var b [4]byte
+	n, err := sr.ReadAt(b[:], 3)
+	if err != io.EOF || string(b[:n]) != "56" {
+		t.Fatalf("n=%d err=%v data=%q", n, err, string(b[:n]))
+	}
+	n, err = sr.ReadAt(b[:2], 0)
+	if err != nil || string(b[:n]) != "23" {
+		t.Fatalf("n=%d err=%v data=%q", n, err, string(b[:n]))
+	}
+	if n, err = sr.ReadAt(b[:], 5); err != io.EOF || n != 0 {
+		t.Fatalf("want (0, EOF) got (%d, %v)", n, err)
+	}
+}
+
+func TestSectionReader_WriterToCopiesWholeSection(t *testing.T) {
+	sr := iox.NewSectionReader(bytes.NewReader(bytes.Repeat([]byte("x"), 100000)), 10, 70000)
+	var dst bytes.Buffer
+	n, err := iox.Copy(&dst, sr)
+	if err != nil || n != 70000 || dst.Len() != 70000 {
+		t.Fatalf("n=%d err=%v len=%d", n, err, dst.Len())
+	}
+}
+
+func TestSectionReader_PropagatesWouldBlock(t *testing.T) {
+	sr := iox.NewSectionReader(&wbOnceReaderAt{data: []byte("hello world")}, 6, 5)
+	var dst bytes.Buffer
+	n, err := iox.Copy(&dst, sr)
+	if !errors.Is(err, iox.ErrWouldBlock) || n != 0 {
+		t.Fatalf("want (0, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	n, err = iox.Copy(&dst, sr)
+	if err != nil || n != 5 || dst.String() != "world" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, dst.String())
+	}
+}
+
+func TestSectionReader_WriterToResumesAfterPartialWrite(t *testing.T) {
+	sr := iox.NewSectionReader(bytes.NewReader([]byte("abcdef")), 0, 6)
+	dst := &gateWriter{quota: 4}
+	n, err := sr.WriteTo(dst)
+	if !errors.Is(err, iox.ErrWouldBlock) || n != 4 {
+		t.Fatalf("want (4, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	dst.quota = 100
+	n, err = sr.WriteTo(dst)
+	if err != nil || n != 2 || dst.buf.String() != "abcdef" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, dst.buf.String())
+	}
+}
+
+// arrivalReader delivers chunks one per Read and would-blocks whenever the
+// next chunk has not "arrived" yet; arrived counts available chunks.
+type arrivalReader struct {
+	chunks  [][]byte
+	arrived int
+	i       int
+}
+
+func (r *arrivalReader) Read(p []byte) (int, error) {
+	if r.i >= len(r.chunks) {
+		return 0, io.EOF
+	}
+	if r.i >= r.arrived {
+		return 0, iox.ErrWouldBlock
+	}
+	n := copy(p, r.chunks[r.i])
+	r.i++
+	return n, nil
+}
+
+func TestBufferedReaderAt_SequentialAndOutOfOrder(t *testing.T) {
+	data := []byte("0123456789abcdefghij")
+	ra := iox.NewBufferedReaderAt(&plainReader{data: data})
+	p := make([]byte, 4)
+	for off := int64(0); off < 20; off += 4 {
+		if n, err := ra.ReadAt(p, off); n != 4 || err != nil || string(p) != string(data[off:off+4]) {
+			t.Fatalf("seq off=%d: (%d,%v,%q)", off, n, err, p[:n])
+		}
+	}
+	for _, off := range []int64{12, 3, 16, 0} {
+		if n, err := ra.ReadAt(p, off); n != 4 || err != nil || string(p) != string(data[off:off+4]) {
+			t.Fatalf("random off=%d: (%d,%v,%q)", off, n, err, p[:n])
+		}
+	}
+	if n, err := ra.ReadAt(p, 18); n != 2 || err != io.EOF || string(p[:n]) != "ij" {
+		t.Fatalf("tail: (%d,%v,%q)", n, err, p[:n])
+	}
+}
+
+func TestBufferedReaderAt_WouldBlockUntilArrival(t *testing.T) {
+	src := &arrivalReader{chunks: [][]byte{[]byte("abcd"), []byte("efgh")}, arrived: 1}
+	ra := iox.NewBufferedReaderAt(src)
+	p := make([]byte, 4)
+
+	// Out-of-order: the second chunk has not arrived yet.
+	if n, err := ra.ReadAt(p, 4); n != 0 || err != iox.ErrWouldBlock {
+		t.Fatalf("not arrived: (%d,%v) want (0,ErrWouldBlock)", n, err)
+	}
+	if n, err := ra.ReadAt(p[:2], 2); n != 2 || err != nil || string(p[:2]) != "cd" {
+		t.Fatalf("cached: (%d,%v,%q)", n, err, p[:n])
+	}
+	if n, err := ra.ReadAt(p, 2); n != 2 || err != iox.ErrWouldBlock || string(p[:n]) != "cd" {
+		t.Fatalf("straddling: (%d,%v,%q)", n, err, p[:n])
+	}
+
+	src.arrived = 2
+	if n, err := ra.ReadAt(p, 4); n != 4 || err != nil || string(p) != "efgh" {
+		t.Fatalf("after arrival: (%d,%v,%q)", n, err, p[:n])
+	}
+	if ra.Buffered() != 8 {
+		t.Fatalf("Buffered()=%d want 8", ra.Buffered())
+	}
+	if _, err := ra.ReadAt(p, -1); err == nil {
+		t.Fatal("negative offset accepted")
+	}
+}
@@ -6,6 +6,9 @@ package iox
 
 import (
 	"errors"
+	"io"
+	"net"
+	"os"
 )
 
 // Outcome classifies an operation result based on iox's extended semantics.
@@ -13,6 +16,9 @@ import (
 // OutcomeOK:            success, no more to come.
 // OutcomeWouldBlock:    no progress is possible right now; retry later.
 // OutcomeMore:          progress happened and more completions are expected.
+// OutcomeTimeout:       waiting for progress timed out; no progress now.
+// OutcomeShortWrite:    a writer accepted fewer bytes than requested (a failure).
+// OutcomeClosed:        the endpoint was closed mid-transfer (a failure).
 // OutcomeFailure:       any other error (including EOF when it's not absorbed by helpers).
 type Outcome uint8
 
@@ -21,6 +27,9 @@ const (
 	OutcomeOK
 	OutcomeWouldBlock
 	OutcomeMore
+	OutcomeTimeout
+	OutcomeShortWrite
+	OutcomeClosed
 )
 
 func (o Outcome) String() string {
@@ -31,11 +40,47 @@ func (o Outcome) String() string {
 		return "WouldBlock"
 	case OutcomeMore:
 		return "More"
+	case OutcomeTimeout:
+		return "Timeout"
+	case OutcomeShortWrite:
+		return "ShortWrite"
+	case OutcomeClosed:
+		return "Closed"
 	default:
 		return "Failure"
 	}
 }
 
+// Exit codes returned by Outcome.Code, following the BSD sysexits.h
+// convention used by CLI tools.
+const (
+	ExitOK       = 0  // success
+	ExitFailure  = 1  // generic failure
+	ExitTempFail = 75 // EX_TEMPFAIL: temporary condition; retrying may succeed
+)
+
+// Code maps o to a process exit code for CLI tools wrapping iox transfers:
+//
+//	OK, More                 -> ExitOK (0); More means progress was made
+//	WouldBlock, Timeout      -> ExitTempFail (75)
+//	ShortWrite, Closed, ...  -> ExitFailure (1)
+//
+// Callers that treat an unfinished multi-shot transfer as an error can check
+// for OutcomeMore before calling Code.
+func (o Outcome) Code() int {
+	switch o {
+	case OutcomeOK, OutcomeMore:
+		return ExitOK
+	case OutcomeWouldBlock, OutcomeTimeout:
+		return ExitTempFail
+	default:
+		return ExitFailure
+	}
+}
+
+// FromError returns the exit code for err: Classify(err).Code().
+func FromError(err error) int { return Classify(err).Code() }
+
 // IsWouldBlock reports whether err carries the iox would-block semantic.
 // It returns true for ErrWouldBlock and wrappers (via errors.Is).
 func IsWouldBlock(err error) bool { return errors.Is(err, ErrWouldBlock) }
@@ -44,16 +89,42 @@ func IsWouldBlock(err error) bool { return errors.Is(err, ErrWouldBlock) }
 // semantic. It returns true for ErrMore and wrappers (via errors.Is).
 func IsMore(err error) bool { return errors.Is(err, ErrMore) }
 
+// IsTimeout reports whether err carries the iox timeout semantic. It returns
+// true for ErrTimeout and wrappers (via errors.Is).
+func IsTimeout(err error) bool { return errors.Is(err, ErrTimeout) }
+
+// IsShortWrite reports whether err is ErrShortWrite (io.ErrShortWrite) or wraps it.
+// A short write is a failure; IsNonFailure reports false for it.
+func IsShortWrite(err error) bool { return errors.Is(err, ErrShortWrite) }
+
+// IsNoSeeker reports whether err is ErrNoSeeker or wraps it: a partial write
+// with a semantic error left unwritten bytes that a non-seekable source could
+// not take back. Like a short write it is a failure, not a retry signal.
+func IsNoSeeker(err error) bool { return errors.Is(err, ErrNoSeeker) }
+
+// IsClosed reports whether err means the endpoint was closed: ErrClosed,
+// net.ErrClosed, os.ErrClosed, or io.ErrClosedPipe, including wrapped forms.
+// A closed endpoint is a failure; IsNonFailure reports false for it.
+func IsClosed(err error) bool {
+	return errors.Is(err, ErrClosed) || errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, os.ErrClosed) || errors.Is(err, io.ErrClosedPipe)
+}
+
 // IsSemantic reports whether err represents an iox semantic signal: either
 // ErrWouldBlock or ErrMore (including wrapped forms).
 func IsSemantic(err error) bool { return IsWouldBlock(err) || IsMore(err) }
 
+// IsRetryable reports whether err means "try the operation again later":
+// ErrWouldBlock, ErrMore, or ErrTimeout, including wrapped forms. It is
+// IsSemantic widened to include timeouts; nil and failures report false.
+func IsRetryable(err error) bool { return IsSemantic(err) || IsTimeout(err) }
+
 // IsNonFailure reports whether err should be treated as a non-failure in
-// non-blocking I/O control flow: nil, ErrWouldBlock, or ErrMore.
+// non-blocking I/O control flow: nil, ErrWouldBlock, ErrMore, or ErrTimeout.
 //
 // Typical usage: decide whether to keep a descriptor active without logging an
 // error or tearing down the operation.
-func IsNonFailure(err error) bool { return err == nil || IsSemantic(err) }
+func IsNonFailure(err error) bool { return err == nil || IsSemantic(err) || IsTimeout(err) }
 
 // IsProgress reports whether the current call produced usable progress now:
 // returns true for nil and ErrMore. In both cases caller can proceed with
@@ -62,8 +133,10 @@ func IsProgress(err error) bool { return err == nil || IsMore(err) }
 
 // Classify maps err to an Outcome. Use when a compact switch is preferred.
 //
-// Note: This does not attempt to reinterpret standard library sentinels like
-// io.EOF; classification depends solely on the error value the caller passes.
+// Note: Apart from io.ErrShortWrite, which maps to OutcomeShortWrite, and the
+// closed-endpoint errors recognized by IsClosed, which map to OutcomeClosed,
+// this does not reinterpret standard library sentinels like io.EOF;
+// classification depends solely on the error value the caller passes.
 func Classify(err error) Outcome {
 	if err == nil {
 		return OutcomeOK
@@ -74,5 +147,82 @@ func Classify(err error) Outcome {
 	if IsMore(err) {
 		return OutcomeMore
 	}
+	if IsTimeout(err) {
+		return OutcomeTimeout
+	}
+	if IsShortWrite(err) {
+		return OutcomeShortWrite
+	}
+	if IsClosed(err) {
+		return OutcomeClosed
+	}
 	return OutcomeFailure
 }
+
+// CombineSemantic merges the errors from two sides of one operation (for
+// example the source and sink of a tee) into a single result, using a fixed
+// precedence so composite helpers agree on which error wins:
+//
+//	generic failure > ErrShortWrite > ErrTimeout > ErrWouldBlock > ErrMore > EOF > nil
+//
+// When a and b rank equally, a is returned. The chosen value is returned
+// as-is, so wrapped errors keep their context.
+func CombineSemantic(a, b error) error {
+	if semanticRank(b) > semanticRank(a) {
+		return b
+	}
+	return a
+}
+
+func semanticRank(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case IsShortWrite(err):
+		return 5
+	case IsTimeout(err):
+		return 4
+	case IsWouldBlock(err):
+		return 3
+	case IsMore(err):
+		return 2
+	case errors.Is(err, EOF):
+		return 1
+	default:
+		return 6
+	}
+}
+
+// OutcomeHandlers holds optional per-outcome callbacks for Handle. Each
+// handler receives the original error and returns the error Handle should
+// report (it may return err unchanged, transform it, or return nil).
+type OutcomeHandlers struct {
+	OnOK         func(err error) error
+	OnWouldBlock func(err error) error
+	OnMore       func(err error) error
+	OnTimeout    func(err error) error
+	OnFailure    func(err error) error // also receives OutcomeShortWrite and OutcomeClosed
+}
+
+// Handle dispatches err to the handler in h selected by Classify(err) and
+// returns that handler's result. If the selected handler is nil, err is
+// returned unchanged.
+func Handle(err error, h OutcomeHandlers) error {
+	var fn func(error) error
+	switch Classify(err) {
+	case OutcomeOK:
+		fn = h.OnOK
+	case OutcomeWouldBlock:
+		fn = h.OnWouldBlock
+	case OutcomeMore:
+		fn = h.OnMore
+	case OutcomeTimeout:
+		fn = h.OnTimeout
+	default:
+		fn = h.OnFailure
+	}
+	if fn == nil {
+		return err
+	}
+	return fn(err)
+}
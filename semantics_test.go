@@ -7,6 +7,9 @@ package iox_test
 import (
 	"errors"
 	"fmt"
+	"io"
+	"net"
+	"os"
 	"testing"
 
 	"code.hybscloud.com/iox"
@@ -32,6 +35,7 @@ func TestSemantics_ClassifyAndPredicates(t *testing.T) {
 		{"nil", nil, false, false, false, true, true, iox.OutcomeOK, "OK"},
 		{"wouldblock", iox.ErrWouldBlock, true, false, true, true, false, iox.OutcomeWouldBlock, "WouldBlock"},
 		{"more", iox.ErrMore, false, true, true, true, true, iox.OutcomeMore, "More"},
+		{"timeout", iox.ErrTimeout, false, false, false, true, false, iox.OutcomeTimeout, "Timeout"},
 		{"sentinelErr", sentinelErr, false, false, false, false, false, iox.OutcomeFailure, "Failure"},
 	}
 	for _, tc := range cases {
@@ -165,3 +169,239 @@ func TestOutcomeStringAndClassify(t *testing.T) {
 		}
 	})
 }
+
+func TestSemantics_Timeout(t *testing.T) {
+	for _, err := range []error{iox.ErrTimeout, fmt.Errorf("read fd 3: %w", iox.ErrTimeout)} {
+		if !iox.IsTimeout(err) {
+			t.Fatalf("IsTimeout(%v)=false", err)
+		}
+		if iox.IsWouldBlock(err) || iox.IsMore(err) || iox.IsSemantic(err) {
+			t.Fatalf("timeout must not match would-block/more: %v", err)
+		}
+		if !iox.IsNonFailure(err) || iox.IsProgress(err) {
+			t.Fatalf("timeout: want non-failure without progress: %v", err)
+		}
+		if got := iox.Classify(err); got != iox.OutcomeTimeout {
+			t.Fatalf("Classify(%v)=%v", err, got)
+		}
+	}
+	if iox.IsTimeout(nil) || iox.IsTimeout(iox.ErrWouldBlock) || iox.IsTimeout(iox.ErrMore) {
+		t.Fatal("IsTimeout true for non-timeout")
+	}
+	if iox.Classify(iox.ErrWouldBlock) != iox.OutcomeWouldBlock || iox.Classify(iox.ErrMore) != iox.OutcomeMore {
+		t.Fatal("existing classification changed")
+	}
+}
+
+func TestWrapWouldBlockAndWrapMore(t *testing.T) {
+	wb := iox.WrapWouldBlock("fd 7")
+	if !errors.Is(wb, iox.ErrWouldBlock) || !iox.IsWouldBlock(wb) || iox.IsMore(wb) {
+		t.Fatalf("wrapped would-block not detected: %v", wb)
+	}
+	if iox.Classify(wb) != iox.OutcomeWouldBlock {
+		t.Fatalf("Classify(%v)=%v", wb, iox.Classify(wb))
+	}
+	if got := wb.Error(); got != "fd 7: io: would block" {
+		t.Fatalf("Error()=%q", got)
+	}
+
+	more := iox.WrapMore("ring 2")
+	if !errors.Is(more, iox.ErrMore) || !iox.IsMore(more) || iox.IsWouldBlock(more) {
+		t.Fatalf("wrapped more not detected: %v", more)
+	}
+	if iox.Classify(more) != iox.OutcomeMore {
+		t.Fatalf("Classify(%v)=%v", more, iox.Classify(more))
+	}
+	if got := more.Error(); got != "ring 2: io: expect more" {
+		t.Fatalf("Error()=%q", got)
+	}
+}
+
+func TestSemantics_ShortWrite(t *testing.T) {
+	for _, err := range []error{io.ErrShortWrite, fmt.Errorf("write fd 4: %w", io.ErrShortWrite)} {
+		if !iox.IsShortWrite(err) {
+			t.Fatalf("IsShortWrite(%v)=false", err)
+		}
+		if iox.IsNonFailure(err) || iox.IsSemantic(err) {
+			t.Fatalf("short write must be a failure: %v", err)
+		}
+		if got := iox.Classify(err); got != iox.OutcomeShortWrite {
+			t.Fatalf("Classify(%v)=%v", err, got)
+		}
+	}
+	if s := iox.OutcomeShortWrite.String(); s != "ShortWrite" {
+		t.Fatalf("OutcomeShortWrite=%q", s)
+	}
+	if iox.IsShortWrite(nil) || iox.IsShortWrite(io.EOF) || iox.IsShortWrite(iox.ErrWouldBlock) {
+		t.Fatal("IsShortWrite true for non-short-write")
+	}
+	if iox.Classify(io.EOF) != iox.OutcomeFailure || iox.Classify(iox.ErrTimeout) != iox.OutcomeTimeout {
+		t.Fatal("existing classification changed")
+	}
+}
+
+func TestCombineSemantic(t *testing.T) {
+	generic := errors.New("boom")
+	// Ordered from highest to lowest precedence.
+	ranked := []error{
+		generic,
+		io.ErrShortWrite,
+		iox.ErrTimeout,
+		iox.ErrWouldBlock,
+		iox.ErrMore,
+		io.EOF,
+		nil,
+	}
+	for i, a := range ranked {
+		for j, b := range ranked {
+			want := a
+			if j < i {
+				want = b
+			}
+			if got := iox.CombineSemantic(a, b); got != want {
+				t.Fatalf("CombineSemantic(%v, %v)=%v want %v", a, b, got, want)
+			}
+		}
+	}
+
+	// Ties keep the first argument; wrapped forms keep their rank and value.
+	other := errors.New("other")
+	if got := iox.CombineSemantic(generic, other); got != generic {
+		t.Fatalf("tie: got %v want %v", got, generic)
+	}
+	wb := fmt.Errorf("fd 3: %w", iox.ErrWouldBlock)
+	if got := iox.CombineSemantic(iox.ErrMore, wb); got != wb {
+		t.Fatalf("wrapped: got %v want %v", got, wb)
+	}
+}
+
+func TestHandle(t *testing.T) {
+	boom := errors.New("boom")
+	var called string
+	tag := func(name string, ret error) func(error) error {
+		return func(error) error { called = name; return ret }
+	}
+	h := iox.OutcomeHandlers{
+		OnOK:         tag("ok", nil),
+		OnWouldBlock: tag("wb", nil),
+		OnMore:       tag("more", iox.ErrMore),
+		OnTimeout:    tag("timeout", boom),
+		OnFailure:    tag("failure", boom),
+	}
+	cases := []struct {
+		err  error
+		name string
+		want error
+	}{
+		{nil, "ok", nil},
+		{iox.ErrWouldBlock, "wb", nil},
+		{fmt.Errorf("x: %w", iox.ErrMore), "more", iox.ErrMore},
+		{iox.ErrTimeout, "timeout", boom},
+		{io.ErrShortWrite, "failure", boom},
+		{io.EOF, "failure", boom},
+	}
+	for _, tc := range cases {
+		called = ""
+		if got := iox.Handle(tc.err, h); got != tc.want || called != tc.name {
+			t.Fatalf("Handle(%v)=%v via %q, want %v via %q", tc.err, got, called, tc.want, tc.name)
+		}
+	}
+
+	// Nil handlers pass the error through.
+	for _, err := range []error{nil, iox.ErrWouldBlock, iox.ErrMore, iox.ErrTimeout, boom} {
+		if got := iox.Handle(err, iox.OutcomeHandlers{}); got != err {
+			t.Fatalf("Handle(%v, {})=%v", err, got)
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	for _, err := range []error{
+		iox.ErrWouldBlock, iox.ErrMore, iox.ErrTimeout,
+		fmt.Errorf("a: %w", iox.ErrWouldBlock),
+		iox.WrapMore("b"),
+		fmt.Errorf("c: %w", iox.ErrTimeout),
+	} {
+		if !iox.IsRetryable(err) {
+			t.Fatalf("IsRetryable(%v)=false", err)
+		}
+	}
+	for _, err := range []error{nil, errors.New("x"), io.EOF, io.ErrShortWrite} {
+		if iox.IsRetryable(err) {
+			t.Fatalf("IsRetryable(%v)=true", err)
+		}
+	}
+}
+
+func TestIsNoSeeker(t *testing.T) {
+	// The slow path reports a partial write it cannot roll back.
+	dst := &partialThenWBWriter{k: 2}
+	n, err := iox.Copy(dst, &plainReader{data: []byte("abcdef")})
+	if n != 2 || err != iox.ErrNoSeeker || !iox.IsNoSeeker(err) {
+		t.Fatalf("Copy=(%d,%v) want (2,ErrNoSeeker)", n, err)
+	}
+
+	wrapped := fmt.Errorf("upload: %w", err)
+	if !errors.Is(wrapped, iox.ErrNoSeeker) || !iox.IsNoSeeker(wrapped) {
+		t.Fatalf("wrapped %v not detected", wrapped)
+	}
+	for _, err := range []error{nil, iox.ErrWouldBlock, io.ErrShortWrite} {
+		if iox.IsNoSeeker(err) {
+			t.Fatalf("IsNoSeeker(%v)=true", err)
+		}
+	}
+}
+
+func TestSemantics_Closed(t *testing.T) {
+	for _, err := range []error{
+		iox.ErrClosed, net.ErrClosed, os.ErrClosed, io.ErrClosedPipe,
+		fmt.Errorf("read fd 7: %w", iox.ErrClosed),
+		&net.OpError{Op: "write", Net: "tcp", Err: net.ErrClosed},
+	} {
+		if !iox.IsClosed(err) {
+			t.Fatalf("IsClosed(%v)=false", err)
+		}
+		if iox.IsNonFailure(err) || iox.IsRetryable(err) {
+			t.Fatalf("closed must be a failure: %v", err)
+		}
+		if got := iox.Classify(err); got != iox.OutcomeClosed {
+			t.Fatalf("Classify(%v)=%v", err, got)
+		}
+	}
+	if s := iox.OutcomeClosed.String(); s != "Closed" {
+		t.Fatalf("OutcomeClosed=%q", s)
+	}
+	if iox.IsClosed(nil) || iox.IsClosed(io.EOF) || iox.IsClosed(iox.ErrWouldBlock) {
+		t.Fatal("IsClosed true for non-closed error")
+	}
+	if iox.Classify(io.EOF) != iox.OutcomeFailure || iox.Classify(io.ErrShortWrite) != iox.OutcomeShortWrite ||
+		iox.Classify(iox.ErrMore) != iox.OutcomeMore || iox.Classify(nil) != iox.OutcomeOK {
+		t.Fatal("existing classification changed")
+	}
+}
+
+func TestOutcome_Code(t *testing.T) {
+	cases := []struct {
+		o    iox.Outcome
+		want int
+	}{
+		{iox.OutcomeOK, 0},
+		{iox.OutcomeMore, 0},
+		{iox.OutcomeWouldBlock, 75},
+		{iox.OutcomeTimeout, 75},
+		{iox.OutcomeShortWrite, 1},
+		{iox.OutcomeClosed, 1},
+		{iox.OutcomeFailure, 1},
+		{iox.Outcome(255), 1},
+	}
+	for _, tc := range cases {
+		if got := tc.o.Code(); got != tc.want {
+			t.Fatalf("%v.Code()=%d want %d", tc.o, got, tc.want)
+		}
+	}
+	if iox.FromError(nil) != iox.ExitOK ||
+		iox.FromError(fmt.Errorf("x: %w", iox.ErrWouldBlock)) != iox.ExitTempFail ||
+		iox.FromError(io.ErrUnexpectedEOF) != iox.ExitFailure {
+		t.Fatal("FromError mapping mismatch")
+	}
+}
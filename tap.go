@@ -0,0 +1,75 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iox
+
+// TappedReader records every byte read from an underlying Reader so that the
+// stream can be replayed from the start, giving non-seekable sources
+// retry-without-loss: after a failed Copy, call Rewind and copy again.
+//
+// Bytes are recorded whenever r.Read returns them, including bytes delivered
+// together with ErrWouldBlock, ErrMore, or any other error. Errors themselves
+// are not recorded: after Rewind, the recorded bytes are served with nil
+// errors, and once they are exhausted Read continues with r (still
+// recording), passing its results through unchanged.
+//
+// Without a limit the recording grows with everything read from r; use
+// NewTappedReaderLimit to bound it for long streams.
+//
+// A TappedReader is not safe for concurrent use.
+type TappedReader struct {
+	r        Reader
+	buf      []byte // recorded bytes
+	pos      int    // replay position in buf
+	limit    int    // max recorded bytes; <= 0 means unbounded
+	overflow bool   // limit exceeded; recording dropped
+}
+
+// NewTappedReader returns a TappedReader reading from r with an unbounded
+// recording.
+func NewTappedReader(r Reader) *TappedReader {
+	return &TappedReader{r: r}
+}
+
+// NewTappedReaderLimit is like NewTappedReader but records at most limit
+// bytes. Once more than limit bytes have been read, the recording is dropped
+// and Rewind reports ErrNoSeeker. If limit <= 0, the recording is unbounded.
+func NewTappedReaderLimit(r Reader, limit int) *TappedReader {
+	return &TappedReader{r: r, limit: limit}
+}
+
+// Read serves recorded bytes pending after a Rewind, then reads from the
+// underlying Reader and records what it returns.
+func (t *TappedReader) Read(p []byte) (n int, err error) {
+	if t.pos < len(t.buf) {
+		n = copy(p, t.buf[t.pos:])
+		t.pos += n
+		return n, nil
+	}
+	n, err = t.r.Read(p)
+	if n > 0 && !t.overflow {
+		if t.limit > 0 && len(t.buf)+n > t.limit {
+			t.overflow = true
+			t.buf = nil
+		} else {
+			t.buf = append(t.buf, p[:n]...)
+		}
+		t.pos = len(t.buf)
+	}
+	return n, err
+}
+
+// Rewind restarts reading from the first recorded byte. It returns
+// ErrNoSeeker if the limit was exceeded and the start can no longer be
+// replayed.
+func (t *TappedReader) Rewind() error {
+	if t.overflow {
+		return ErrNoSeeker
+	}
+	t.pos = 0
+	return nil
+}
+
+// Recorded returns the number of bytes held in the recording.
+func (t *TappedReader) Recorded() int { return len(t.buf) }
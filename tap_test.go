@@ -0,0 +1,75 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iox_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"code.hybscloud.com/iox"
+)
+
+func TestTappedReader_RewindReplaysIdenticalBytes(t *testing.T) {
+	tr := iox.NewTappedReader(&plainReader{data: []byte("hello world")})
+	var p [5]byte
+	if n, err := tr.Read(p[:]); n != 5 || err != nil || string(p[:n]) != "hello" {
+		t.Fatalf("first=(%d,%v,%q)", n, err, p[:n])
+	}
+	if err := tr.Rewind(); err != nil {
+		t.Fatalf("rewind: %v", err)
+	}
+	var dst bytes.Buffer
+	if n, err := iox.Copy(&dst, tr); err != nil || n != 11 {
+		t.Fatalf("copy=(%d,%v)", n, err)
+	}
+	if dst.String() != "hello world" || tr.Recorded() != 11 {
+		t.Fatalf("dst=%q recorded=%d", dst.String(), tr.Recorded())
+	}
+}
+
+func TestTappedReader_RecordsBytesWithWouldBlock(t *testing.T) {
+	src := &scriptedReader{steps: []scriptStep{{b: []byte("ab"), err: iox.ErrWouldBlock}, {err: iox.ErrWouldBlock}, {b: []byte("cd")}}}
+	tr := iox.NewTappedReader(src)
+	var first bytes.Buffer
+	n, err := iox.Copy(&first, tr)
+	if !errors.Is(err, iox.ErrWouldBlock) || n != 2 {
+		t.Fatalf("first pass=(%d,%v) want (2,ErrWouldBlock)", n, err)
+	}
+	if n, err = iox.Copy(&first, tr); !errors.Is(err, iox.ErrWouldBlock) || n != 0 {
+		t.Fatalf("second pass=(%d,%v) want (0,ErrWouldBlock)", n, err)
+	}
+	if err := tr.Rewind(); err != nil {
+		t.Fatalf("rewind: %v", err)
+	}
+	var replay bytes.Buffer
+	if n, err = iox.Copy(&replay, tr); err != nil || n != 4 {
+		t.Fatalf("replay=(%d,%v)", n, err)
+	}
+	if replay.String() != "abcd" || tr.Recorded() != 4 {
+		t.Fatalf("replay=%q recorded=%d", replay.String(), tr.Recorded())
+	}
+}
+
+func TestTappedReader_LimitExceeded(t *testing.T) {
+	tr := iox.NewTappedReaderLimit(&plainReader{data: []byte("abcdef")}, 4)
+	var p [4]byte
+	if n, err := tr.Read(p[:]); n != 4 || err != nil {
+		t.Fatalf("read=(%d,%v)", n, err)
+	}
+	if err := tr.Rewind(); err != nil {
+		t.Fatalf("rewind within limit: %v", err)
+	}
+	var dst bytes.Buffer
+	if _, err := iox.Copy(&dst, tr); err != nil || dst.String() != "abcdef" {
+		t.Fatalf("copy err=%v dst=%q", err, dst.String())
+	}
+	if err := tr.Rewind(); !errors.Is(err, iox.ErrNoSeeker) {
+		t.Fatalf("want ErrNoSeeker got %v", err)
+	}
+	if tr.Recorded() != 0 {
+		t.Fatalf("recorded=%d after overflow", tr.Recorded())
+	}
+}
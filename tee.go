@@ -4,7 +4,13 @@
 
 package iox
 
-import "io"
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 // TeeReader returns a Reader that writes to w what it reads from r.
 // It mirrors io.TeeReader but propagates iox semantics:
@@ -17,7 +23,16 @@ import "io"
 //   - The returned n is the number of bytes read from r.
 //   - If the side write fails or is short, n is still the read count.
 //     This avoids byte loss: the bytes were already consumed from r.
+//
+// A zero-length p is a readiness probe: r.Read(p) is called once and its
+// result, including ErrWouldBlock, is returned as is without a side write.
+// The policy variants follow the same contract and never retry a probe.
+//
+// If r or w is nil, every Read returns (0, ErrNilArgument).
 func TeeReader(r Reader, w Writer) Reader {
+	if r == nil || w == nil {
+		return nilArgument{}
+	}
 	return teeReader{r: r, w: w}
 }
 
@@ -29,12 +44,40 @@ func TeeReader(r Reader, w Writer) Reader {
 //     semantics when n>0, the bytes are delivered to the caller after side write,
 //     and a retry decision of PolicyRetry results in returning (n, nil).
 func TeeReaderPolicy(r Reader, w Writer, policy SemanticPolicy) Reader {
-	if policy == nil {
+	if policy == nil || r == nil || w == nil {
+		return TeeReader(r, w)
+	}
+	return teeReaderWithPolicy{r: r, w: w, rp: policy, sp: policy}
+}
+
+// TeeReaderPolicy2 is like TeeReaderPolicy but uses separate policies for the
+// two sides: readPolicy is consulted for r.Read semantics (OpTeeReaderRead)
+// and sidePolicy for the side write to w (OpTeeReaderSideWrite).
+//
+// A nil policy for either side means ReturnPolicy for that side. If both are
+// nil, the result is identical to TeeReader.
+func TeeReaderPolicy2(r Reader, w Writer, readPolicy, sidePolicy SemanticPolicy) Reader {
+	if readPolicy == nil && sidePolicy == nil || r == nil || w == nil {
 		return TeeReader(r, w)
 	}
-	return teeReaderWithPolicy{r: r, w: w, p: policy}
+	if readPolicy == nil {
+		readPolicy = ReturnPolicy{}
+	}
+	if sidePolicy == nil {
+		sidePolicy = ReturnPolicy{}
+	}
+	return teeReaderWithPolicy{r: r, w: w, rp: readPolicy, sp: sidePolicy}
 }
 
+// nilArgument stands in for a Tee adapter built with a nil Reader or Writer.
+type nilArgument struct{}
+
+func (nilArgument) Read([]byte) (int, error) { return 0, ErrNilArgument }
+
+func (nilArgument) Write([]byte) (int, error) { return 0, ErrNilArgument }
+
+func (nilArgument) Close() error { return ErrNilArgument }
+
 type teeReader struct {
 	r Reader
 	w Writer
@@ -42,7 +85,7 @@ type teeReader struct {
 
 func (t teeReader) Read(p []byte) (n int, err error) {
 	n, err = t.r.Read(p)
-	if n > 0 {
+	if n > 0 && len(p) > 0 {
 		nw, ew := t.w.Write(p[:n])
 		if ew != nil {
 			return n, ew
@@ -56,34 +99,40 @@ func (t teeReader) Read(p []byte) (n int, err error) {
 }
 
 type teeReaderWithPolicy struct {
-	r Reader
-	w Writer
-	p SemanticPolicy
+	r  Reader
+	w  Writer
+	rp SemanticPolicy // read-side policy
+	sp SemanticPolicy // side-write policy
 }
 
 func (t teeReaderWithPolicy) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return t.r.Read(p)
+	}
 	for {
 		n, er := t.r.Read(p)
+		progress(t.rp, OpTeeReaderRead, n)
 		if n > 0 {
 			// Write to side, retrying on policy if needed.
 			// Note: returned n must remain the read count to avoid byte loss.
 			off := 0
 			for off < n {
 				nw, ew := t.w.Write(p[off:n])
+				progress(t.sp, OpTeeReaderSideWrite, nw)
 				if nw > 0 {
 					off += nw
 				}
 				if ew != nil {
-					if ew == ErrWouldBlock {
-						if t.p.OnWouldBlock(OpTeeReaderSideWrite) == PolicyRetry {
-							t.p.Yield(OpTeeReaderSideWrite)
+					if IsWouldBlock(ew) {
+						if t.sp.OnWouldBlock(OpTeeReaderSideWrite) == PolicyRetry {
+							t.sp.Yield(OpTeeReaderSideWrite)
 							continue
 						}
 						return n, ew
 					}
-					if ew == ErrMore {
-						if t.p.OnMore(OpTeeReaderSideWrite) == PolicyRetry {
-							t.p.Yield(OpTeeReaderSideWrite)
+					if IsMore(ew) {
+						if t.sp.OnMore(OpTeeReaderSideWrite) == PolicyRetry {
+							t.sp.Yield(OpTeeReaderSideWrite)
 							continue
 						}
 						return n, ew
@@ -96,17 +145,17 @@ func (t teeReaderWithPolicy) Read(p []byte) (int, error) {
 			}
 
 			// After side write completes, decide based on read-side semantic.
-			if er == ErrWouldBlock {
-				if t.p.OnWouldBlock(OpTeeReaderRead) == PolicyRetry {
-					t.p.Yield(OpTeeReaderRead)
+			if IsWouldBlock(er) {
+				if t.rp.OnWouldBlock(OpTeeReaderRead) == PolicyRetry {
+					t.rp.Yield(OpTeeReaderRead)
 					// Treat as successful read for this call.
 					return n, nil
 				}
 				return n, er
 			}
-			if er == ErrMore {
-				if t.p.OnMore(OpTeeReaderRead) == PolicyRetry {
-					t.p.Yield(OpTeeReaderRead)
+			if IsMore(er) {
+				if t.rp.OnMore(OpTeeReaderRead) == PolicyRetry {
+					t.rp.Yield(OpTeeReaderRead)
 					return n, nil
 				}
 				return n, er
@@ -115,16 +164,16 @@ func (t teeReaderWithPolicy) Read(p []byte) (int, error) {
 		}
 
 		// n == 0 path: we may need to loop on policy retry.
-		if er == ErrWouldBlock {
-			if t.p.OnWouldBlock(OpTeeReaderRead) == PolicyRetry {
-				t.p.Yield(OpTeeReaderRead)
+		if IsWouldBlock(er) {
+			if t.rp.OnWouldBlock(OpTeeReaderRead) == PolicyRetry {
+				t.rp.Yield(OpTeeReaderRead)
 				continue
 			}
 			return 0, er
 		}
-		if er == ErrMore {
-			if t.p.OnMore(OpTeeReaderRead) == PolicyRetry {
-				t.p.Yield(OpTeeReaderRead)
+		if IsMore(er) {
+			if t.rp.OnMore(OpTeeReaderRead) == PolicyRetry {
+				t.rp.Yield(OpTeeReaderRead)
 				continue
 			}
 			return 0, er
@@ -133,6 +182,315 @@ func (t teeReaderWithPolicy) Read(p []byte) (int, error) {
 	}
 }
 
+// TeeReadCloser is like TeeReader but also owns the side writer: Close closes
+// both r and w and reports their errors joined with errors.Join.
+//
+// Both Close methods are always invoked, in order r then w, regardless of
+// earlier Read or side-write failures.
+//
+// If r or w is nil, Read and Close return ErrNilArgument.
+func TeeReadCloser(r ReadCloser, w WriteCloser) ReadCloser {
+	if r == nil || w == nil {
+		return nilArgument{}
+	}
+	return teeReadCloser{teeReader: teeReader{r: r, w: w}, rc: r, wc: w}
+}
+
+type teeReadCloser struct {
+	teeReader
+	rc ReadCloser
+	wc WriteCloser
+}
+
+func (t teeReadCloser) Close() error {
+	return errors.Join(t.rc.Close(), t.wc.Close())
+}
+
+// AsyncTeeReader is like TeeReader but writes to w on a background goroutine,
+// so Read never waits on a slow side sink (e.g., an audit log). Each chunk
+// read from r is copied and queued; up to queueDepth chunks may be pending
+// (a queueDepth <= 0 means 1).
+//
+// Backpressure: if the queue is full, Read returns (0, ErrWouldBlock) without
+// reading from r, so no byte is read that cannot be teed.
+//
+// ErrWouldBlock / ErrMore from w are transient: the writer goroutine waits
+// with a Backoff and retries the unwritten rest of the chunk. Other side-write
+// failures (including short writes, reported as io.ErrShortWrite) are
+// sticky: once the writer goroutine records one, it stops writing and the
+// next Read returns (0, err).
+//
+// Close stops accepting chunks, waits for the queue to drain, and returns the
+// side-write error, if any. It does not wait for a Read blocked in r: bytes
+// that such a Read returns after Close are delivered to the caller but not
+// teed. Read after Close returns ErrClosedPipe. Close does not close r or w.
+// Close may run concurrently with a Read, but the returned ReadCloser is not
+// safe for concurrent Reads.
+//
+// If r or w is nil, no goroutine is started and Read and Close return
+// ErrNilArgument.
+func AsyncTeeReader(r Reader, w Writer, queueDepth int) ReadCloser {
+	if r == nil || w == nil {
+		return nilArgument{}
+	}
+	if queueDepth <= 0 {
+		queueDepth = 1
+	}
+	t := &asyncTeeReader{r: r, queue: make(chan []byte, queueDepth), done: make(chan struct{})}
+	go t.run(w)
+	return t
+}
+
+type asyncTeeReader struct {
+	r     Reader
+	queue chan []byte
+	done  chan struct{}
+
+	qmu    sync.Mutex // guards closed and sends on queue
+	closed bool
+
+	mu  sync.Mutex
+	err error // first hard side-write error
+}
+
+func (t *asyncTeeReader) run(w Writer) {
+	defer close(t.done)
+	var b Backoff
+	for chunk := range t.queue {
+		for len(chunk) > 0 && t.sideErr() == nil {
+			n, err := w.Write(chunk)
+			chunk = chunk[n:]
+			if IsSemantic(err) {
+				if n > 0 {
+					b.Reset()
+				}
+				b.Wait()
+				continue
+			}
+			b.Reset()
+			if err == nil && len(chunk) > 0 {
+				err = io.ErrShortWrite
+			}
+			if err != nil {
+				t.mu.Lock()
+				t.err = err
+				t.mu.Unlock()
+			}
+		}
+	}
+}
+
+func (t *asyncTeeReader) sideErr() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+func (t *asyncTeeReader) Read(p []byte) (n int, err error) {
+	t.qmu.Lock()
+	closed, full := t.closed, len(t.queue) == cap(t.queue)
+	t.qmu.Unlock()
+	if closed {
+		return 0, ErrClosedPipe
+	}
+	if err := t.sideErr(); err != nil {
+		return 0, err
+	}
+	if full {
+		return 0, ErrWouldBlock
+	}
+	n, err = t.r.Read(p)
+	if n > 0 {
+		// Reads are not concurrent and the writer goroutine only drains, so
+		// the slot seen above is still free and the send cannot block.
+		t.qmu.Lock()
+		if !t.closed {
+			t.queue <- append([]byte(nil), p[:n]...)
+		}
+		t.qmu.Unlock()
+	}
+	return n, err
+}
+
+func (t *asyncTeeReader) Close() error {
+	t.qmu.Lock()
+	if !t.closed {
+		t.closed = true
+		close(t.queue)
+	}
+	t.qmu.Unlock()
+	<-t.done
+	return t.sideErr()
+}
+
+// MultiTeeReader returns a Reader that writes to every w in ws what it reads
+// from r. It is the fan-out form of TeeReader.
+//
+// Each chunk read from r is written to all sinks, in order, before Read
+// returns; a failing sink does not prevent the remaining sinks from receiving
+// the chunk.
+//
+// Error precedence:
+//   - If any sink short-writes, io.ErrShortWrite is returned.
+//   - Otherwise the first non-nil sink error is returned.
+//   - Otherwise the error from r.Read (including ErrWouldBlock / ErrMore) is
+//     returned unchanged.
+//
+// Count semantics: the returned n is always the number of bytes read from r.
+//
+// If r or any w is nil, every Read returns (0, ErrNilArgument).
+func MultiTeeReader(r Reader, ws ...Writer) Reader {
+	if r == nil {
+		return nilArgument{}
+	}
+	for _, w := range ws {
+		if w == nil {
+			return nilArgument{}
+		}
+	}
+	sinks := make([]Writer, len(ws))
+	copy(sinks, ws)
+	return multiTeeReader{r: r, ws: sinks}
+}
+
+type multiTeeReader struct {
+	r  Reader
+	ws []Writer
+}
+
+func (t multiTeeReader) Read(p []byte) (n int, err error) {
+	n, err = t.r.Read(p)
+	if n > 0 {
+		short := false
+		var first error
+		for _, w := range t.ws {
+			nw, ew := w.Write(p[:n])
+			if ew != nil {
+				if first == nil {
+					first = ew
+				}
+				continue
+			}
+			if nw != n {
+				short = true
+			}
+		}
+		if short {
+			return n, io.ErrShortWrite
+		}
+		if first != nil {
+			return n, first
+		}
+	}
+	return n, err
+}
+
+// SampleTeeReader is like TeeReader but mirrors only a sampled fraction of
+// the stream to w, e.g., forwarding about 1% of traffic to an observability
+// sink. Each chunk read from r is written to w with probability rate, drawn
+// from an internal PRNG seeded from the clock; a rate <= 0 forwards nothing
+// and a rate >= 1 forwards every chunk.
+//
+// Sampling is per chunk, not per byte: a forwarded chunk is mirrored whole,
+// so the sampled share of bytes depends on read sizes. The full read is
+// always returned to the caller, and semantics and side-write errors are
+// reported as in TeeReader. The returned Reader is not safe for concurrent
+// use.
+func SampleTeeReader(r Reader, w Writer, rate float64) Reader {
+	return SampleTeeReaderSeed(r, w, rate, uint64(time.Now().UnixNano()))
+}
+
+// SampleTeeReaderSeed is like SampleTeeReader but seeds the PRNG with seed,
+// so the sequence of sampled chunks is reproducible.
+func SampleTeeReaderSeed(r Reader, w Writer, rate float64, seed uint64) Reader {
+	if r == nil || w == nil {
+		return nilArgument{}
+	}
+	return &sampleTeeReader{r: r, w: w, rate: rate, state: seed | 1}
+}
+
+type sampleTeeReader struct {
+	r     Reader
+	w     Writer
+	rate  float64
+	state uint64 // xorshift PRNG state
+}
+
+func (t *sampleTeeReader) Read(p []byte) (n int, err error) {
+	n, err = t.r.Read(p)
+	if n > 0 && t.sample() {
+		nw, ew := t.w.Write(p[:n])
+		if ew != nil {
+			return n, ew
+		}
+		if nw != n {
+			return n, io.ErrShortWrite
+		}
+	}
+	return n, err
+}
+
+// sample reports whether the next chunk is forwarded.
+func (t *sampleTeeReader) sample() bool {
+	if t.rate <= 0 {
+		return false
+	}
+	if t.rate >= 1 {
+		return true
+	}
+	t.state ^= t.state << 13
+	t.state ^= t.state >> 7
+	t.state ^= t.state << 17
+	return float64(t.state>>11)/(1<<53) < t.rate
+}
+
+// TransformTeeReader is like TeeReader but mirrors a transformed copy of each
+// chunk to w (e.g., uppercased or hex-encoded for a debug sink), while Read
+// still returns the original bytes.
+//
+// After each read of n > 0 bytes, transform(dst, p[:n]) fills dst, a scratch
+// buffer owned by the reader, and returns the number of bytes to write to w.
+// If the result does not fit, transform returns the size it needs instead,
+// without relying on dst; the buffer is grown to that size and transform is
+// called again. Since transform may change the length, w can receive a
+// different byte count than Read returns; a short side write is still
+// reported as io.ErrShortWrite, and side errors are returned as in TeeReader.
+func TransformTeeReader(r Reader, w Writer, transform func(dst, src []byte) int) Reader {
+	if r == nil || w == nil {
+		return nilArgument{}
+	}
+	return &transformTeeReader{r: r, w: w, transform: transform}
+}
+
+type transformTeeReader struct {
+	r         Reader
+	w         Writer
+	transform func(dst, src []byte) int
+	buf       []byte // scratch buffer for transformed bytes
+}
+
+func (t *transformTeeReader) Read(p []byte) (n int, err error) {
+	n, err = t.r.Read(p)
+	if n > 0 {
+		if len(t.buf) < n {
+			t.buf = make([]byte, n)
+		}
+		m := t.transform(t.buf, p[:n])
+		if m > len(t.buf) {
+			t.buf = make([]byte, m)
+			m = t.transform(t.buf, p[:n])
+		}
+		nw, ew := t.w.Write(t.buf[:m])
+		if ew != nil {
+			return n, ew
+		}
+		if nw != m {
+			return n, io.ErrShortWrite
+		}
+	}
+	return n, err
+}
+
 // TeeWriter returns a Writer that writes to primary and also mirrors the bytes
 // accepted by primary to tee.
 //
@@ -150,7 +508,12 @@ func (t teeReaderWithPolicy) Read(p []byte) (int, error) {
 //   - If the tee write fails after primary has accepted bytes, n is still the
 //     primary count. This makes retry-by-slicing (p[n:]) safe: it will not
 //     duplicate primary writes.
+//
+// If primary or tee is nil, every Write returns (0, ErrNilArgument).
 func TeeWriter(primary Writer, tee Writer) Writer {
+	if primary == nil || tee == nil {
+		return nilArgument{}
+	}
 	return teeWriter{w: primary, tee: tee}
 }
 
@@ -160,12 +523,80 @@ func TeeWriter(primary Writer, tee Writer) Writer {
 //   - non-nil: PolicyRetry yields and retries writing remaining bytes for either
 //     the primary or tee writes. Short writes are reported as io.ErrShortWrite.
 func TeeWriterPolicy(primary Writer, tee Writer, policy SemanticPolicy) Writer {
-	if policy == nil {
+	if policy == nil || primary == nil || tee == nil {
 		return TeeWriter(primary, tee)
 	}
 	return teeWriterWithPolicy{w: primary, tee: tee, p: policy}
 }
 
+// TeeWriterBestEffort is like TeeWriter but never lets the tee affect the
+// primary write. Tee failures, including short tee writes (reported as
+// io.ErrShortWrite), are passed to onTeeErr instead of being returned.
+//
+// The returned (n, err) reflects only the primary write, so callers see the
+// same results they would get from writing to primary directly (with a short
+// primary write reported as io.ErrShortWrite). onTeeErr may be nil, in which
+// case tee errors are dropped.
+func TeeWriterBestEffort(primary, tee Writer, onTeeErr func(error)) Writer {
+	if primary == nil || tee == nil {
+		return nilArgument{}
+	}
+	return teeWriterBestEffort{w: primary, tee: tee, onTeeErr: onTeeErr}
+}
+
+// TeeWriterCounts is like TeeWriter but also tracks the cumulative bytes
+// accepted by each side across all Write calls. The returned counts function
+// reports (primaryN, teeN); teeN falls below primaryN when the tee fails or
+// short-writes. counts is safe to call concurrently with Write.
+//
+// If primary or tee is nil, every Write returns (0, ErrNilArgument) and
+// counts reports (0, 0).
+func TeeWriterCounts(primary, tee Writer) (w Writer, counts func() (primaryN, teeN int64)) {
+	if primary == nil || tee == nil {
+		return nilArgument{}, func() (int64, int64) { return 0, 0 }
+	}
+	c := &teeWriterCounting{w: primary, tee: tee}
+	return c, func() (int64, int64) { return c.pn.Load(), c.tn.Load() }
+}
+
+// TeeWriterFramed is like TeeWriter for framed protocols in which primary
+// returns ErrMore to mark a completed frame. Bytes accepted by primary are
+// accumulated, and when primary returns ErrMore the whole accumulated frame
+// is forwarded to tee in a single Write.
+//
+// Bytes after the last frame boundary stay pending until the next ErrMore.
+// A frame is handed to tee once: if the tee write fails or is short, that
+// error (or io.ErrShortWrite) is returned and the frame is dropped.
+// Otherwise the primary result, including ErrMore, is returned unchanged.
+//
+// Count semantics match TeeWriter: n is the number of bytes accepted by
+// primary.
+func TeeWriterFramed(primary, tee Writer) Writer {
+	if primary == nil || tee == nil {
+		return nilArgument{}
+	}
+	return &teeWriterFramed{w: primary, tee: tee}
+}
+
+// FailoverTeeWriter is like TeeWriter but, for HA mirroring, fails over to a
+// backup sink: bytes are mirrored to teePrimary until it fails, and from then
+// on to teeBackup for good.
+//
+// Only failures trigger failover: a generic error, or a short tee write with
+// a nil error. The bytes of that chunk that teePrimary did not take are
+// written to teeBackup in the same call, and the primary result is returned.
+// ErrWouldBlock and ErrMore from the tee are transient and are returned as in
+// TeeWriter without switching. Errors from teeBackup are returned as they
+// are; there is no further failover.
+//
+// The returned Writer is not safe for concurrent use.
+func FailoverTeeWriter(primary Writer, teePrimary, teeBackup Writer) Writer {
+	if primary == nil || teePrimary == nil || teeBackup == nil {
+		return nilArgument{}
+	}
+	return &failoverTeeWriter{w: primary, tee: teePrimary, backup: teeBackup}
+}
+
 type teeWriter struct {
 	w   Writer
 	tee Writer
@@ -191,6 +622,129 @@ func (t teeWriter) Write(p []byte) (n int, err error) {
 	return n, nil
 }
 
+type failoverTeeWriter struct {
+	w      Writer
+	tee    Writer // current tee sink
+	backup Writer // nil once failed over
+}
+
+func (t *failoverTeeWriter) Write(p []byte) (n int, err error) {
+	n, err = t.w.Write(p)
+	if n > 0 {
+		chunk := p[:n]
+		n2, err2 := t.tee.Write(chunk)
+		if err2 == nil && n2 != len(chunk) {
+			err2 = io.ErrShortWrite
+		}
+		if err2 != nil && !IsSemantic(err2) && t.backup != nil {
+			t.tee, t.backup = t.backup, nil
+			chunk = chunk[n2:]
+			n2, err2 = t.tee.Write(chunk)
+			if err2 == nil && n2 != len(chunk) {
+				err2 = io.ErrShortWrite
+			}
+		}
+		if err2 != nil {
+			return n, err2
+		}
+	}
+	if err != nil {
+		return n, err
+	}
+	if n != len(p) {
+		return n, io.ErrShortWrite
+	}
+	return n, nil
+}
+
+type teeWriterCounting struct {
+	w   Writer
+	tee Writer
+	pn  atomic.Int64
+	tn  atomic.Int64
+}
+
+func (t *teeWriterCounting) Write(p []byte) (n int, err error) {
+	n, err = t.w.Write(p)
+	if n > 0 {
+		t.pn.Add(int64(n))
+		n2, err2 := t.tee.Write(p[:n])
+		if n2 > 0 {
+			t.tn.Add(int64(n2))
+		}
+		if err2 != nil {
+			return n, err2
+		}
+		if n2 != n {
+			return n, io.ErrShortWrite
+		}
+	}
+	if err != nil {
+		return n, err
+	}
+	if n != len(p) {
+		return n, io.ErrShortWrite
+	}
+	return n, nil
+}
+
+type teeWriterFramed struct {
+	w     Writer
+	tee   Writer
+	frame []byte
+}
+
+func (t *teeWriterFramed) Write(p []byte) (n int, err error) {
+	n, err = t.w.Write(p)
+	if n > 0 {
+		t.frame = append(t.frame, p[:n]...)
+	}
+	if IsMore(err) && len(t.frame) > 0 {
+		n2, err2 := t.tee.Write(t.frame)
+		short := n2 != len(t.frame)
+		t.frame = t.frame[:0]
+		if err2 != nil {
+			return n, err2
+		}
+		if short {
+			return n, io.ErrShortWrite
+		}
+	}
+	if err != nil {
+		return n, err
+	}
+	if n != len(p) {
+		return n, io.ErrShortWrite
+	}
+	return n, nil
+}
+
+type teeWriterBestEffort struct {
+	w        Writer
+	tee      Writer
+	onTeeErr func(error)
+}
+
+func (t teeWriterBestEffort) Write(p []byte) (n int, err error) {
+	n, err = t.w.Write(p)
+	if n > 0 {
+		n2, err2 := t.tee.Write(p[:n])
+		if err2 == nil && n2 != n {
+			err2 = io.ErrShortWrite
+		}
+		if err2 != nil && t.onTeeErr != nil {
+			t.onTeeErr(err2)
+		}
+	}
+	if err != nil {
+		return n, err
+	}
+	if n != len(p) {
+		return n, io.ErrShortWrite
+	}
+	return n, nil
+}
+
 type teeWriterWithPolicy struct {
 	w   Writer
 	tee Writer
@@ -203,24 +757,26 @@ func (t teeWriterWithPolicy) Write(p []byte) (int, error) {
 	off := 0
 	for off < len(p) {
 		nw, ew := t.w.Write(p[off:])
+		progress(t.p, OpTeeWriterPrimaryWrite, nw)
 		if nw > 0 {
 			// Mirror the newly accepted bytes to tee.
 			teeOff := 0
 			chunk := p[off : off+nw]
 			for teeOff < len(chunk) {
 				n2, e2 := t.tee.Write(chunk[teeOff:])
+				progress(t.p, OpTeeWriterTeeWrite, n2)
 				if n2 > 0 {
 					teeOff += n2
 				}
 				if e2 != nil {
-					if e2 == ErrWouldBlock {
+					if IsWouldBlock(e2) {
 						if t.p.OnWouldBlock(OpTeeWriterTeeWrite) == PolicyRetry {
 							t.p.Yield(OpTeeWriterTeeWrite)
 							continue
 						}
 						return off + nw, e2
 					}
-					if e2 == ErrMore {
+					if IsMore(e2) {
 						if t.p.OnMore(OpTeeWriterTeeWrite) == PolicyRetry {
 							t.p.Yield(OpTeeWriterTeeWrite)
 							continue
@@ -236,14 +792,14 @@ func (t teeWriterWithPolicy) Write(p []byte) (int, error) {
 			off += nw
 		}
 		if ew != nil {
-			if ew == ErrWouldBlock {
+			if IsWouldBlock(ew) {
 				if t.p.OnWouldBlock(OpTeeWriterPrimaryWrite) == PolicyRetry {
 					t.p.Yield(OpTeeWriterPrimaryWrite)
 					continue
 				}
 				return off, ew
 			}
-			if ew == ErrMore {
+			if IsMore(ew) {
 				if t.p.OnMore(OpTeeWriterPrimaryWrite) == PolicyRetry {
 					t.p.Yield(OpTeeWriterPrimaryWrite)
 					continue
@@ -282,3 +838,27 @@ func AsWriterTo(r Reader) Reader { return WriterToAdapter{R: r} }
 
 // AsReaderFrom wraps w so that it also implements ReaderFrom via iox semantics.
 func AsReaderFrom(w Writer) Writer { return ReaderFromAdapter{W: w} }
+
+// NopCloser returns a ReadCloser with a no-op Close method wrapping r.
+//
+// Unlike io.NopCloser, the result also implements WriterTo (forwarding to r)
+// when r implements WriterTo, so Copy keeps using the fast path.
+func NopCloser(r Reader) ReadCloser {
+	if wt, ok := r.(WriterTo); ok {
+		return nopCloserWriterTo{Reader: r, wt: wt}
+	}
+	return nopCloser{Reader: r}
+}
+
+type nopCloser struct{ Reader }
+
+func (nopCloser) Close() error { return nil }
+
+type nopCloserWriterTo struct {
+	Reader
+	wt WriterTo
+}
+
+func (nopCloserWriterTo) Close() error { return nil }
+
+func (c nopCloserWriterTo) WriteTo(w Writer) (int64, error) { return c.wt.WriteTo(w) }
@@ -6,8 +6,12 @@ package iox_test
 
 import (
 	"bytes"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"code.hybscloud.com/iox"
 )
@@ -447,7 +451,7 @@ func TestTeeReaderPolicy_ReadZero_WouldBlock_Returns(t *testing.T) {
 func TestTeeReaderPolicy_ReadZero_WouldBlock_RetryThenEOF(t *testing.T) {
 	tr := iox.TeeReaderPolicy(&rWBThenEOF{}, &bytes.Buffer{}, &recPolicy{onWB: map[iox.Op]iox.PolicyAction{iox.OpTeeReaderRead: iox.PolicyRetry}})
 	var b [1]byte
-	n, err := tr.Read(b[:0])
+	n, err := tr.Read(b[:])
 	if err != iox.EOF || n != 0 {
 		t.Fatalf("want (0, EOF) got (%d, %v)", n, err)
 	}
@@ -463,8 +467,748 @@ func TestTeeReaderPolicy_ReadZero_More_Returns(t *testing.T) {
 func TestTeeReaderPolicy_ReadZero_More_RetryThenEOF(t *testing.T) {
 	tr := iox.TeeReaderPolicy(&rMoreThenEOF{}, &bytes.Buffer{}, &recPolicy{onMore: map[iox.Op]iox.PolicyAction{iox.OpTeeReaderRead: iox.PolicyRetry}})
 	var b [1]byte
-	n, err := tr.Read(b[:0])
+	n, err := tr.Read(b[:])
 	if err != iox.EOF || n != 0 {
 		t.Fatalf("want (0, EOF) got (%d, %v)", n, err)
 	}
 }
+
+func TestTeeReaderPolicy2_ReadWouldBlockReturned_SideMoreRetried(t *testing.T) {
+	r := &dataThenErrReader{data: []byte("log"), err: iox.ErrWouldBlock}
+	var side sideMoreOnce
+	sidePol := &recPolicy{onMore: map[iox.Op]iox.PolicyAction{iox.OpTeeReaderSideWrite: iox.PolicyRetry}}
+	tr := iox.TeeReaderPolicy2(r, &side, nil, sidePol)
+	buf := make([]byte, 8)
+	n, err := tr.Read(buf)
+	if !errors.Is(err, iox.ErrWouldBlock) || n != 3 {
+		t.Fatalf("want (3, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+	if side.buf.String() != "log" || string(buf[:n]) != "log" {
+		t.Fatalf("side=%q read=%q", side.buf.String(), string(buf[:n]))
+	}
+	if len(sidePol.yields) != 1 || sidePol.yields[0] != iox.OpTeeReaderSideWrite {
+		t.Fatalf("side yields=%v", sidePol.yields)
+	}
+}
+
+func TestTeeReaderPolicy2_PoliciesAreNotShared(t *testing.T) {
+	readPol := &recPolicy{onWB: map[iox.Op]iox.PolicyAction{iox.OpTeeReaderRead: iox.PolicyRetry}}
+	sidePol := &recPolicy{}
+	tr := iox.TeeReaderPolicy2(&rWBThenEOF{}, sideWB{}, readPol, sidePol)
+	var b [1]byte
+	n, err := tr.Read(b[:])
+	if err != iox.EOF || n != 0 {
+		t.Fatalf("want (0, EOF) got (%d, %v)", n, err)
+	}
+	if len(readPol.yields) != 1 || len(sidePol.yields) != 0 {
+		t.Fatalf("read yields=%v side yields=%v", readPol.yields, sidePol.yields)
+	}
+
+	// Side write would-block consults only the side policy (nil -> return).
+	tr = iox.TeeReaderPolicy2(bytes.NewBufferString("x"), sideWB{}, readPol, nil)
+	n, err = tr.Read(b[:])
+	if !errors.Is(err, iox.ErrWouldBlock) || n != 1 {
+		t.Fatalf("want (1, ErrWouldBlock) got (%d, %v)", n, err)
+	}
+}
+
+func TestTeeReaderPolicy2_BothNil_IsTeeReader(t *testing.T) {
+	var side bytes.Buffer
+	tr := iox.TeeReaderPolicy2(bytes.NewBufferString("abc"), &side, nil, nil)
+	buf := make([]byte, 8)
+	n, err := tr.Read(buf)
+	if err != nil || n != 3 || side.String() != "abc" {
+		t.Fatalf("n=%d err=%v side=%q", n, err, side.String())
+	}
+}
+
+func TestMultiTeeReader_AllSinksReceiveIdenticalBytes(t *testing.T) {
+	r := &scriptedReader{steps: []struct {
+		b   []byte
+		err error
+	}{{b: []byte("abc")}, {b: []byte("def")}, {b: []byte("gh")}}}
+	var a, b, c bytes.Buffer
+	tr := iox.MultiTeeReader(r, &a, &b, &c)
+	var got bytes.Buffer
+	buf := make([]byte, 4)
+	for {
+		n, err := tr.Read(buf)
+		got.Write(buf[:n])
+		if err == iox.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+	}
+	for i, s := range []string{got.String(), a.String(), b.String(), c.String()} {
+		if s != "abcdefgh" {
+			t.Fatalf("sink %d got %q", i, s)
+		}
+	}
+}
+
+func TestMultiTeeReader_ShortWriteOnSecondSink(t *testing.T) {
+	var first, third bytes.Buffer
+	tr := iox.MultiTeeReader(bytes.NewBufferString("hello"), &first, shortWriter{limit: 2}, &third)
+	buf := make([]byte, 8)
+	n, err := tr.Read(buf)
+	if !errors.Is(err, iox.ErrShortWrite) || n != 5 {
+		t.Fatalf("want (5, ErrShortWrite) got (%d, %v)", n, err)
+	}
+	if first.String() != "hello" || third.String() != "hello" {
+		t.Fatalf("first=%q third=%q", first.String(), third.String())
+	}
+}
+
+func TestMultiTeeReader_FirstSinkErrorAndReadSemantics(t *testing.T) {
+	e1 := errors.New("sink1")
+	e2 := errors.New("sink2")
+	tr := iox.MultiTeeReader(bytes.NewBufferString("x"), &bytes.Buffer{}, errZeroWriter{err: e1}, errZeroWriter{err: e2})
+	var b [1]byte
+	if n, err := tr.Read(b[:]); !errors.Is(err, e1) || n != 1 {
+		t.Fatalf("want (1, sink1) got (%d, %v)", n, err)
+	}
+
+	var side bytes.Buffer
+	tr = iox.MultiTeeReader(&dataThenErrReader{data: []byte("zz"), err: iox.ErrMore}, &side)
+	buf := make([]byte, 4)
+	n, err := tr.Read(buf)
+	if !errors.Is(err, iox.ErrMore) || n != 2 || side.String() != "zz" {
+		t.Fatalf("n=%d err=%v side=%q", n, err, side.String())
+	}
+}
+
+func TestTeeWriterBestEffort_PrimarySucceedsWhenTeeFails(t *testing.T) {
+	teeErr := errors.New("tee down")
+	var primary bytes.Buffer
+	var got []error
+	w := iox.TeeWriterBestEffort(&primary, errZeroWriter{err: teeErr}, func(err error) { got = append(got, err) })
+	for _, chunk := range []string{"ab", "cd"} {
+		n, err := w.Write([]byte(chunk))
+		if err != nil || n != 2 {
+			t.Fatalf("n=%d err=%v", n, err)
+		}
+	}
+	if primary.String() != "abcd" {
+		t.Fatalf("primary=%q", primary.String())
+	}
+	if len(got) != 2 || !errors.Is(got[0], teeErr) || !errors.Is(got[1], teeErr) {
+		t.Fatalf("onTeeErr got %v", got)
+	}
+}
+
+func TestTeeWriterBestEffort_TeeShortWriteRoutedToCallback(t *testing.T) {
+	var primary bytes.Buffer
+	var got error
+	w := iox.TeeWriterBestEffort(&primary, shortWriter{limit: 1}, func(err error) { got = err })
+	n, err := w.Write([]byte("xyz"))
+	if err != nil || n != 3 || primary.String() != "xyz" {
+		t.Fatalf("n=%d err=%v primary=%q", n, err, primary.String())
+	}
+	if !errors.Is(got, iox.ErrShortWrite) {
+		t.Fatalf("onTeeErr got %v", got)
+	}
+}
+
+func TestTeeWriterBestEffort_ReturnsPrimaryResult(t *testing.T) {
+	var tee bytes.Buffer
+	w := iox.TeeWriterBestEffort(&partialWBWriter{partial: 2}, &tee, nil)
+	n, err := w.Write([]byte("hello"))
+	if !errors.Is(err, iox.ErrWouldBlock) || n != 2 || tee.String() != "he" {
+		t.Fatalf("n=%d err=%v tee=%q", n, err, tee.String())
+	}
+	w = iox.TeeWriterBestEffort(shortWriter{limit: 1}, errZeroWriter{err: errors.New("ignored")}, nil)
+	n, err = w.Write([]byte("ab"))
+	if !errors.Is(err, iox.ErrShortWrite) || n != 1 {
+		t.Fatalf("want (1, ErrShortWrite) got (%d, %v)", n, err)
+	}
+}
+
+// closeRecorder wraps a Reader/Writer and records Close calls.
+type closeRecorder struct {
+	r      iox.Reader
+	w      iox.Writer
+	err    error
+	closed int
+}
+
+func (c *closeRecorder) Read(p []byte) (int, error)  { return c.r.Read(p) }
+func (c *closeRecorder) Write(p []byte) (int, error) { return c.w.Write(p) }
+func (c *closeRecorder) Close() error                { c.closed++; return c.err }
+
+func TestTeeReadCloser_ReadTeesAndCloseClosesBoth(t *testing.T) {
+	var side bytes.Buffer
+	rc := &closeRecorder{r: bytes.NewBufferString("data")}
+	wc := &closeRecorder{w: &side}
+	trc := iox.TeeReadCloser(rc, wc)
+	buf := make([]byte, 8)
+	n, err := trc.Read(buf)
+	if err != nil || n != 4 || side.String() != "data" {
+		t.Fatalf("n=%d err=%v side=%q", n, err, side.String())
+	}
+	if err := trc.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if rc.closed != 1 || wc.closed != 1 {
+		t.Fatalf("closed r=%d w=%d", rc.closed, wc.closed)
+	}
+}
+
+func TestTeeReadCloser_SideWriteErrorDoesNotPreventClose(t *testing.T) {
+	writeErr := errors.New("side write")
+	rErr := errors.New("reader close")
+	wErr := errors.New("side close")
+	rc := &closeRecorder{r: bytes.NewBufferString("x"), err: rErr}
+	wc := &closeRecorder{w: errZeroWriter{err: writeErr}, err: wErr}
+	trc := iox.TeeReadCloser(rc, wc)
+	var b [1]byte
+	if n, err := trc.Read(b[:]); !errors.Is(err, writeErr) || n != 1 {
+		t.Fatalf("want (1, side write) got (%d, %v)", n, err)
+	}
+	err := trc.Close()
+	if !errors.Is(err, rErr) || !errors.Is(err, wErr) {
+		t.Fatalf("close err=%v, want both errors", err)
+	}
+	if rc.closed != 1 || wc.closed != 1 {
+		t.Fatalf("closed r=%d w=%d", rc.closed, wc.closed)
+	}
+}
+
+func TestNopCloser_PreservesWriterTo(t *testing.T) {
+	rc := iox.NopCloser(bytes.NewReader([]byte("fast")))
+	if _, ok := rc.(iox.WriterTo); !ok {
+		t.Fatal("NopCloser dropped WriterTo")
+	}
+	var dst bytes.Buffer
+	n, err := iox.Copy(&dst, rc)
+	if err != nil || n != 4 || dst.String() != "fast" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, dst.String())
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatalf("Close()=%v", err)
+	}
+	// Semantic errors from the forwarded WriteTo are preserved.
+	rc = iox.NopCloser(wtReader{n: 2, err: iox.ErrMore})
+	if n, err := iox.Copy(&dst, rc); !errors.Is(err, iox.ErrMore) || n != 2 {
+		t.Fatalf("want (2, ErrMore) got (%d, %v)", n, err)
+	}
+}
+
+func TestNopCloser_PlainReader(t *testing.T) {
+	rc := iox.NopCloser(&plainReader{data: []byte("slow")})
+	if _, ok := rc.(iox.WriterTo); ok {
+		t.Fatal("plain reader should not gain WriterTo")
+	}
+	buf := make([]byte, 8)
+	n, err := rc.Read(buf)
+	if err != nil || string(buf[:n]) != "slow" {
+		t.Fatalf("n=%d err=%v", n, err)
+	}
+	if err := rc.Close(); err != nil {
+		t.Fatalf("Close()=%v", err)
+	}
+}
+
+func TestTeeWriterCounts(t *testing.T) {
+	var primary, side bytes.Buffer
+	w, counts := iox.TeeWriterCounts(&primary, &side)
+	for _, s := range []string{"abc", "de", "fghij"} {
+		if n, err := w.Write([]byte(s)); err != nil || n != len(s) {
+			t.Fatalf("Write(%q)=(%d,%v)", s, n, err)
+		}
+	}
+	if pn, tn := counts(); pn != 10 || tn != 10 {
+		t.Fatalf("counts=(%d,%d) want (10,10)", pn, tn)
+	}
+	if side.String() != "abcdefghij" {
+		t.Fatalf("side=%q", side.String())
+	}
+
+	// A short tee is reflected in the smaller tee count.
+	var p2 bytes.Buffer
+	w, counts = iox.TeeWriterCounts(&p2, shortWriter{limit: 2})
+	for i := 0; i < 3; i++ {
+		n, err := w.Write([]byte("wxyz"))
+		if n != 4 || err != iox.ErrShortWrite {
+			t.Fatalf("write %d: (%d,%v) want (4, ErrShortWrite)", i, n, err)
+		}
+	}
+	if pn, tn := counts(); pn != 12 || tn != 6 {
+		t.Fatalf("short tee counts=(%d,%d) want (12,6)", pn, tn)
+	}
+}
+
+// framedPrimary accepts all bytes and returns ErrMore each time the running
+// total reaches a multiple of frame.
+type framedPrimary struct {
+	frame int
+	total int
+}
+
+func (w *framedPrimary) Write(p []byte) (int, error) {
+	n := len(p)
+	if rem := w.frame - w.total%w.frame; n > rem {
+		n = rem
+	}
+	w.total += n
+	if w.total%w.frame == 0 {
+		return n, iox.ErrMore
+	}
+	return n, nil
+}
+
+// chunkRecorder records each Write as a separate chunk.
+type chunkRecorder struct{ chunks []string }
+
+func (w *chunkRecorder) Write(p []byte) (int, error) {
+	w.chunks = append(w.chunks, string(p))
+	return len(p), nil
+}
+
+func TestTeeWriterFramed_WholeFrames(t *testing.T) {
+	side := &chunkRecorder{}
+	w := iox.TeeWriterFramed(&framedPrimary{frame: 5}, side)
+
+	// Feed "helloworld" in awkward pieces.
+	var boundaries int
+	for _, s := range []string{"he", "llo", "wor", "ld"} {
+		n, err := w.Write([]byte(s))
+		if n != len(s) {
+			t.Fatalf("Write(%q) n=%d", s, n)
+		}
+		if err == iox.ErrMore {
+			boundaries++
+		} else if err != nil {
+			t.Fatalf("Write(%q) err=%v", s, err)
+		}
+	}
+	if boundaries != 2 {
+		t.Fatalf("boundaries=%d want 2", boundaries)
+	}
+	if len(side.chunks) != 2 || side.chunks[0] != "hello" || side.chunks[1] != "world" {
+		t.Fatalf("tee chunks=%q", side.chunks)
+	}
+
+	// Pending bytes are not forwarded until the next boundary.
+	if _, err := w.Write([]byte("ab")); err != nil {
+		t.Fatalf("pending write err=%v", err)
+	}
+	if len(side.chunks) != 2 {
+		t.Fatalf("partial frame forwarded: %q", side.chunks)
+	}
+}
+
+func TestTeeWriterFramed_TeeShortWrite(t *testing.T) {
+	w := iox.TeeWriterFramed(&framedPrimary{frame: 4}, shortWriter{limit: 2})
+	n, err := w.Write([]byte("abcd"))
+	if n != 4 || err != iox.ErrShortWrite {
+		t.Fatalf("(%d,%v) want (4,ErrShortWrite)", n, err)
+	}
+}
+
+// gatedSink blocks every Write until release is closed, then records it.
+type gatedSink struct {
+	release chan struct{}
+	mu      sync.Mutex
+	buf     bytes.Buffer
+}
+
+func (w *gatedSink) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func TestAsyncTeeReader_Backpressure(t *testing.T) {
+	const depth = 2
+	side := &gatedSink{release: make(chan struct{})}
+	src := &plainReader{data: bytes.Repeat([]byte("x"), 100)}
+	tr := iox.AsyncTeeReader(src, side, depth)
+
+	p := make([]byte, 5)
+	var got int
+	blocked := false
+	// The writer goroutine holds at most one chunk, so the queue is full
+	// after at most depth+1 successful reads.
+	for i := 0; i < depth+2; i++ {
+		n, err := tr.Read(p)
+		if err == iox.ErrWouldBlock {
+			if n != 0 {
+				t.Fatalf("would-block with n=%d", n)
+			}
+			blocked = true
+			break
+		}
+		if err != nil {
+			t.Fatalf("read %d: %v", i, err)
+		}
+		got += n
+	}
+	if !blocked {
+		t.Fatal("full queue did not report ErrWouldBlock")
+	}
+	close(side.release)
+	if err := tr.Close(); err != nil {
+		t.Fatalf("Close()=%v", err)
+	}
+	if side.buf.Len() != got {
+		t.Fatalf("side got %d bytes, main read %d", side.buf.Len(), got)
+	}
+	if _, err := tr.Read(p); err != iox.ErrClosedPipe {
+		t.Fatalf("Read after Close: %v", err)
+	}
+}
+
+func TestAsyncTeeReader_DeferredSideError(t *testing.T) {
+	boom := errors.New("boom")
+	src := &plainReader{data: bytes.Repeat([]byte("y"), 64)}
+	tr := iox.AsyncTeeReader(src, errWriter{err: boom}, 4)
+
+	p := make([]byte, 4)
+	if n, err := tr.Read(p); n != 4 || err != nil {
+		t.Fatalf("first read=(%d,%v)", n, err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		_, err := tr.Read(p)
+		if err == boom {
+			break
+		}
+		if err != nil && err != iox.ErrWouldBlock && err != iox.EOF {
+			t.Fatalf("unexpected %v", err)
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("side error never surfaced")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := tr.Close(); err != boom {
+		t.Fatalf("Close()=%v want boom", err)
+	}
+}
+
+func TestAsyncTeeReader_CloseDuringRead(t *testing.T) {
+	src := &plainReader{data: bytes.Repeat([]byte("z"), 1<<16)}
+	tr := iox.AsyncTeeReader(src, &bytes.Buffer{}, 1)
+
+	stopped := make(chan error, 1)
+	go func() {
+		p := make([]byte, 16)
+		for {
+			if _, err := tr.Read(p); err == iox.ErrClosedPipe {
+				stopped <- err
+				return
+			}
+		}
+	}()
+	time.Sleep(time.Millisecond)
+	if err := tr.Close(); err != nil {
+		t.Fatalf("Close()=%v", err)
+	}
+	if err := <-stopped; err != iox.ErrClosedPipe {
+		t.Fatalf("Read after Close: %v", err)
+	}
+}
+
+// blockedReader signals entered, then blocks in Read until release closes.
+type blockedReader struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (r *blockedReader) Read(p []byte) (int, error) {
+	close(r.entered)
+	<-r.release
+	return copy(p, "late"), nil
+}
+
+func TestAsyncTeeReader_CloseDuringBlockedRead(t *testing.T) {
+	src := &blockedReader{entered: make(chan struct{}), release: make(chan struct{})}
+	var side sliceWriter
+	tr := iox.AsyncTeeReader(src, &side, 1)
+
+	read := make(chan int, 1)
+	go func() {
+		n, _ := tr.Read(make([]byte, 8))
+		read <- n
+	}()
+	<-src.entered
+	closed := make(chan error, 1)
+	go func() { closed <- tr.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatalf("Close()=%v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close waited for a Read blocked in the source")
+	}
+	close(src.release)
+	if n := <-read; n != 4 {
+		t.Fatalf("blocked Read returned n=%d want 4", n)
+	}
+	if len(side.data) != 0 {
+		t.Fatalf("bytes read after Close were teed: %q", side.data)
+	}
+}
+
+// wbOnceSideWriter accepts half of its first write with ErrWouldBlock and
+// everything afterwards.
+type wbOnceSideWriter struct {
+	mu      sync.Mutex
+	stalled bool
+	buf     bytes.Buffer
+}
+
+func (w *wbOnceSideWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.stalled {
+		w.stalled = true
+		n, _ := w.buf.Write(p[:len(p)/2])
+		return n, iox.ErrWouldBlock
+	}
+	return w.buf.Write(p)
+}
+
+func TestAsyncTeeReader_SideWouldBlockIsTransient(t *testing.T) {
+	side := &wbOnceSideWriter{}
+	tr := iox.AsyncTeeReader(&plainReader{data: []byte("abcdefgh")}, side, 4)
+	var got bytes.Buffer
+	p := make([]byte, 4)
+	for {
+		n, err := tr.Read(p)
+		got.Write(p[:n])
+		if err == iox.EOF {
+			break
+		}
+		if err != nil && err != iox.ErrWouldBlock {
+			t.Fatalf("Read: %v", err)
+		}
+	}
+	if err := tr.Close(); err != nil {
+		t.Fatalf("Close()=%v", err)
+	}
+	if got.String() != "abcdefgh" || side.buf.String() != "abcdefgh" {
+		t.Fatalf("main=%q side=%q", got.String(), side.buf.String())
+	}
+}
+
+func TestTee_NilArgument(t *testing.T) {
+	var buf bytes.Buffer
+	src := bytes.NewReader([]byte("abc"))
+	readers := []struct {
+		name string
+		r    iox.Reader
+		w    iox.Writer
+	}{
+		{"nil reader", nil, &buf},
+		{"nil writer", src, nil},
+		{"both nil", nil, nil},
+	}
+	p := make([]byte, 4)
+	for _, tc := range readers {
+		for _, tr := range []iox.Reader{
+			iox.TeeReader(tc.r, tc.w),
+			iox.TeeReaderPolicy(tc.r, tc.w, iox.YieldPolicy{}),
+			iox.TeeReaderPolicy2(tc.r, tc.w, iox.YieldPolicy{}, nil),
+			iox.MultiTeeReader(tc.r, tc.w),
+			iox.MultiTeeReader(tc.r, &buf, tc.w),
+			iox.AsyncTeeReader(tc.r, tc.w, 1),
+		} {
+			if n, err := tr.Read(p); n != 0 || err != iox.ErrNilArgument {
+				t.Errorf("%s: Read=(%d,%v) want (0,ErrNilArgument)", tc.name, n, err)
+			}
+		}
+		for _, tw := range []iox.Writer{
+			iox.TeeWriter(tc.w, tc.w),
+			iox.TeeWriter(&buf, tc.w),
+			iox.TeeWriterPolicy(tc.w, &buf, iox.YieldPolicy{}),
+			iox.TeeWriterBestEffort(tc.w, &buf, nil),
+			iox.TeeWriterBestEffort(&buf, tc.w, nil),
+			iox.TeeWriterFramed(tc.w, &buf),
+			iox.TeeWriterFramed(&buf, tc.w),
+			teeWriterCounts(tc.w, &buf),
+			teeWriterCounts(&buf, tc.w),
+		} {
+			if tc.w != nil {
+				continue
+			}
+			if n, err := tw.Write([]byte("x")); n != 0 || err != iox.ErrNilArgument {
+				t.Errorf("%s: Write=(%d,%v) want (0,ErrNilArgument)", tc.name, n, err)
+			}
+		}
+	}
+	if src.Len() != 3 || buf.Len() != 0 {
+		t.Fatalf("I/O performed: src.Len()=%d buf.Len()=%d", src.Len(), buf.Len())
+	}
+
+	for _, rc := range []iox.ReadCloser{
+		iox.TeeReadCloser(nil, &closeRecorder{w: &buf}),
+		iox.TeeReadCloser(&closeRecorder{r: src}, nil),
+		iox.AsyncTeeReader(nil, &buf, 1),
+	} {
+		if n, err := rc.Read(p); n != 0 || err != iox.ErrNilArgument {
+			t.Errorf("ReadCloser Read=(%d,%v) want (0,ErrNilArgument)", n, err)
+		}
+		if err := rc.Close(); err != iox.ErrNilArgument {
+			t.Errorf("ReadCloser Close=%v want ErrNilArgument", err)
+		}
+	}
+	if _, counts := iox.TeeWriterCounts(nil, &buf); counts == nil {
+		t.Error("TeeWriterCounts returned a nil counts function")
+	} else if pn, tn := counts(); pn != 0 || tn != 0 {
+		t.Errorf("counts=(%d,%d) want (0,0)", pn, tn)
+	}
+}
+
+// teeWriterCounts returns only the Writer of iox.TeeWriterCounts.
+func teeWriterCounts(primary, tee iox.Writer) iox.Writer {
+	w, _ := iox.TeeWriterCounts(primary, tee)
+	return w
+}
+
+func TestTeeReader_ZeroLengthProbe(t *testing.T) {
+	var side sliceWriter
+	pol := &recPolicy{onWB: map[iox.Op]iox.PolicyAction{iox.OpTeeReaderRead: iox.PolicyRetry}}
+	b := make([]byte, 4)
+	for _, tr := range []iox.Reader{
+		iox.TeeReader(errReaderAlwaysWB{}, &side),
+		iox.TeeReaderPolicy(errReaderAlwaysWB{}, &side, pol),
+	} {
+		// A retrying policy must not spin on a probe.
+		if n, err := tr.Read(b[:0]); n != 0 || err != iox.ErrWouldBlock {
+			t.Fatalf("probe=(%d,%v) want (0,ErrWouldBlock)", n, err)
+		}
+	}
+	// A ready source reports (0, nil) and nothing reaches the side writer.
+	src := bytes.NewReader([]byte("data"))
+	tr := iox.TeeReaderPolicy(src, &side, pol)
+	if n, err := tr.Read(b[:0]); n != 0 || err != nil {
+		t.Fatalf("ready probe=(%d,%v) want (0,nil)", n, err)
+	}
+	if len(side.data) != 0 || len(pol.yields) != 0 || src.Len() != 4 {
+		t.Fatalf("probe had effects: side=%q yields=%v src.Len()=%d", side.data, pol.yields, src.Len())
+	}
+}
+
+func TestSampleTeeReader(t *testing.T) {
+	chunks := make([][]byte, 100)
+	for i := range chunks {
+		chunks[i] = []byte{byte(i)}
+	}
+	run := func(r iox.Reader) []byte {
+		var got []byte
+		p := make([]byte, 1)
+		for {
+			n, err := r.Read(p)
+			got = append(got, p[:n]...)
+			if err == iox.EOF {
+				return got
+			}
+			if err != nil && err != iox.ErrMore {
+				t.Fatalf("err=%v", err)
+			}
+		}
+	}
+
+	for _, tc := range []struct {
+		rate float64
+		want int
+	}{{1.0, 100}, {0.0, 0}} {
+		side := &chunkRecorder{}
+		got := run(iox.SampleTeeReader(&moreChunksReader{chunks: chunks}, side, tc.rate))
+		if len(got) != 100 || len(side.chunks) != tc.want {
+			t.Fatalf("rate=%v: read %d bytes, forwarded %d chunks want %d", tc.rate, len(got), len(side.chunks), tc.want)
+		}
+	}
+
+	// A fixed seed reproduces the same sample, and the rate is roughly kept.
+	var sampled [2][]string
+	for i := range sampled {
+		side := &chunkRecorder{}
+		run(iox.SampleTeeReaderSeed(&moreChunksReader{chunks: chunks}, side, 0.3, 42))
+		sampled[i] = side.chunks
+	}
+	if fmt.Sprint(sampled[0]) != fmt.Sprint(sampled[1]) {
+		t.Fatal("same seed produced different samples")
+	}
+	if n := len(sampled[0]); n < 15 || n > 45 {
+		t.Fatalf("rate=0.3 forwarded %d of 100 chunks", n)
+	}
+}
+
+func TestTransformTeeReader_Identity(t *testing.T) {
+	var side bytes.Buffer
+	tr := iox.TransformTeeReader(&plainReader{data: []byte("hello world")}, &side, func(dst, src []byte) int {
+		return copy(dst, src)
+	})
+	var dst bytes.Buffer
+	if n, err := iox.Copy(&dst, tr); n != 11 || err != nil {
+		t.Fatalf("n=%d err=%v", n, err)
+	}
+	if dst.String() != "hello world" || side.String() != "hello world" {
+		t.Fatalf("dst=%q side=%q", dst.String(), side.String())
+	}
+}
+
+func TestTransformTeeReader_HexGrowsLength(t *testing.T) {
+	var side bytes.Buffer
+	tr := iox.TransformTeeReader(&plainReader{data: []byte("iox")}, &side, func(dst, src []byte) int {
+		if need := hex.EncodedLen(len(src)); len(dst) < need {
+			return need
+		}
+		return hex.Encode(dst, src)
+	})
+	var p [8]byte
+	n, err := tr.Read(p[:])
+	if n != 3 || err != nil || string(p[:n]) != "iox" {
+		t.Fatalf("read=(%d,%v,%q)", n, err, p[:n])
+	}
+	if side.String() != "696f78" {
+		t.Fatalf("side=%q want 696f78", side.String())
+	}
+
+	short := iox.TransformTeeReader(&plainReader{data: []byte("ab")}, &shortWriter{limit: 1}, func(dst, src []byte) int {
+		return copy(dst, src)
+	})
+	if n, err := short.Read(p[:]); n != 2 || !errors.Is(err, iox.ErrShortWrite) {
+		t.Fatalf("short side=(%d,%v) want (2,ErrShortWrite)", n, err)
+	}
+}
+
+func TestFailoverTeeWriter_SwitchesOnFailure(t *testing.T) {
+	var dst, backup sliceWriter
+	boom := errors.New("boom")
+	w := iox.FailoverTeeWriter(&dst, &failAfterWriter{k: 3, err: boom}, &backup)
+	if n, err := w.Write([]byte("abcd")); n != 4 || err != nil {
+		t.Fatalf("failover write: n=%d err=%v", n, err)
+	}
+	if string(backup.data) != "d" {
+		t.Fatalf("backup=%q want the unmirrored tail d", backup.data)
+	}
+	if n, err := w.Write([]byte("ef")); n != 2 || err != nil {
+		t.Fatalf("n=%d err=%v", n, err)
+	}
+	if string(dst.data) != "abcdef" || string(backup.data) != "def" {
+		t.Fatalf("dst=%q backup=%q", dst.data, backup.data)
+	}
+}
+
+func TestFailoverTeeWriter_SemanticIsTransient(t *testing.T) {
+	var dst, backup sliceWriter
+	w := iox.FailoverTeeWriter(&dst, wbOnlyWriter{}, &backup)
+	for range 2 {
+		if n, err := w.Write([]byte("ab")); n != 2 || !errors.Is(err, iox.ErrWouldBlock) {
+			t.Fatalf("want (2, ErrWouldBlock) got (%d, %v)", n, err)
+		}
+	}
+	if len(backup.data) != 0 {
+		t.Fatalf("backup=%q, want no failover on ErrWouldBlock", backup.data)
+	}
+	if _, err := iox.FailoverTeeWriter(&dst, nil, &backup).Write([]byte("x")); !errors.Is(err, iox.ErrNilArgument) {
+		t.Fatalf("nil tee: err=%v", err)
+	}
+}
@@ -0,0 +1,282 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iox
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// TimeoutReader returns a Reader whose Read gives up after d and returns
+// (0, ErrTimeout) if r.Read has not completed by then. Data and errors from r
+// (including ErrWouldBlock / ErrMore) are passed through unchanged. A d <= 0
+// returns r itself.
+//
+// Each r.Read runs on its own goroutine and reads into an internal buffer, so
+// the caller's p is never written after Read returns. A read that times out
+// is not cancelled: it keeps running, and its result is delivered by the next
+// Read call (which waits up to d again) instead of starting a second read. No
+// data is lost, but if r.Read never returns, its goroutine and buffer leak.
+//
+// The returned Reader is not safe for concurrent use.
+func TimeoutReader(r Reader, d time.Duration) Reader {
+	if d <= 0 {
+		return r
+	}
+	return &timeoutReader{r: r, d: d}
+}
+
+type timeoutReader struct {
+	r       Reader
+	d       time.Duration
+	buf     []byte
+	pending chan timeoutResult // in-flight read, if any
+	rest    []byte             // delivered data that did not fit in p
+	restErr error              // error to report after rest is drained
+}
+
+type timeoutResult struct {
+	n   int
+	err error
+}
+
+func (t *timeoutReader) Read(p []byte) (n int, err error) {
+	if len(t.rest) > 0 {
+		return t.drain(p)
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if t.pending == nil {
+		if cap(t.buf) < len(p) {
+			t.buf = make([]byte, len(p))
+		}
+		buf := t.buf[:len(p)]
+		ch := make(chan timeoutResult, 1)
+		go func() {
+			n, err := t.r.Read(buf)
+			ch <- timeoutResult{n: n, err: err}
+		}()
+		t.pending = ch
+	}
+
+	timer := time.NewTimer(t.d)
+	defer timer.Stop()
+	select {
+	case res := <-t.pending:
+		t.pending = nil
+		t.rest, t.restErr = t.buf[:res.n], res.err
+		return t.drain(p)
+	case <-timer.C:
+		return 0, ErrTimeout
+	}
+}
+
+// drain delivers buffered data, reporting the read's error with its last byte.
+func (t *timeoutReader) drain(p []byte) (n int, err error) {
+	n = copy(p, t.rest)
+	t.rest = t.rest[n:]
+	if len(t.rest) > 0 {
+		return n, nil
+	}
+	err, t.restErr = t.restErr, nil
+	return n, err
+}
+
+// DeadlineReader returns a Reader that blocks on a non-blocking r for at most
+// budget per Read: on (0, ErrWouldBlock) from r it waits with b and retries
+// until data arrives or budget has elapsed since the Read started, and then
+// returns (0, ErrTimeout).
+//
+// Data from r is returned with a nil error, even if r reported ErrWouldBlock
+// alongside it, and restarts b for the next stall. ErrMore, EOF, and other
+// errors pass through unchanged. The budget is checked after each wait, so a
+// Read can overrun it by up to one Backoff sleep. A nil b uses a zero-value
+// Backoff owned by the reader; a budget <= 0 returns r itself.
+//
+// The returned Reader is not safe for concurrent use.
+func DeadlineReader(r Reader, b *Backoff, budget time.Duration) Reader {
+	if budget <= 0 {
+		return r
+	}
+	if b == nil {
+		b = &Backoff{}
+	}
+	return &budgetReader{r: r, b: b, budget: budget}
+}
+
+type budgetReader struct {
+	r      Reader
+	b      *Backoff
+	budget time.Duration
+}
+
+func (t *budgetReader) Read(p []byte) (n int, err error) {
+	start := time.Now()
+	for {
+		n, err = t.r.Read(p)
+		if n > 0 {
+			t.b.Reset()
+			if IsWouldBlock(err) {
+				err = nil
+			}
+			return n, err
+		}
+		if !IsWouldBlock(err) {
+			return n, err
+		}
+		if time.Since(start) >= t.budget {
+			return 0, ErrTimeout
+		}
+		t.b.Wait()
+	}
+}
+
+// CopyDeadline is like Copy but bounds every blocking step by d, so a stuck
+// peer surfaces as ErrTimeout instead of hanging the copy.
+//
+// If src (or dst) implements SetDeadline(time.Time) error, as net.Conn does,
+// its deadline is set to time.Now().Add(d) before each Read (or Write) and
+// cleared when CopyDeadline returns. An i/o timeout error from such a step
+// (os.ErrDeadlineExceeded, or any error with Timeout() reporting true) is
+// returned as ErrTimeout together with the bytes written so far. ErrWouldBlock
+// and ErrMore keep their Copy semantics. A failure to clear a deadline is
+// returned if the copy itself succeeded.
+//
+// Each side's SetDeadline is probed once up front; a side that reports
+// os.ErrNoDeadline (e.g., an *os.File on a regular file) is copied without a
+// deadline. When neither side has deadlines, or d <= 0, CopyDeadline is Copy.
+// Deadline sides are driven through the read/write loop, since a WriterTo /
+// ReaderFrom fast path could block past the deadline; a seekable src keeps
+// the loop's Seeker rollback.
+func CopyDeadline(dst Writer, src Reader, d time.Duration) (written int64, err error) {
+	if d <= 0 {
+		return Copy(dst, src)
+	}
+	sd, srcOK, err := probeDeadline(src)
+	if err != nil {
+		return 0, err
+	}
+	dd, dstOK, err := probeDeadline(dst)
+	if err != nil {
+		return 0, err
+	}
+	if !srcOK && !dstOK {
+		return Copy(dst, src)
+	}
+	if srcOK {
+		defer clearDeadline(sd, &err)
+		r := deadlineReader{r: src, dl: sd, d: d}
+		if s, ok := src.(Seeker); ok {
+			src = deadlineReadSeeker{deadlineReader: r, s: s}
+		} else {
+			src = r
+		}
+	}
+	if dstOK {
+		defer clearDeadline(dd, &err)
+		dst = deadlineWriter{w: dst, dl: dd, d: d}
+	}
+	return copyBuffer(dst, src, nil, &CopyOpts{DisableFastPath: true})
+}
+
+// deadliner is implemented by net.Conn, *os.File, and similar types. Some
+// implementations report os.ErrNoDeadline (see probeDeadline).
+type deadliner interface {
+	SetDeadline(t time.Time) error
+}
+
+// probeDeadline reports whether x supports deadlines by clearing its
+// deadline once. os.ErrNoDeadline means no support; other errors are
+// returned.
+func probeDeadline(x any) (deadliner, bool, error) {
+	dl, ok := x.(deadliner)
+	if !ok {
+		return nil, false, nil
+	}
+	if err := dl.SetDeadline(time.Time{}); err != nil {
+		if errors.Is(err, os.ErrNoDeadline) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return dl, true, nil
+}
+
+// clearDeadline clears dl's deadline and stores a failure in *err unless
+// *err already holds an error.
+func clearDeadline(dl deadliner, err *error) {
+	if e := dl.SetDeadline(time.Time{}); e != nil && *err == nil {
+		*err = e
+	}
+}
+
+type deadlineReader struct {
+	r  Reader
+	dl deadliner
+	d  time.Duration
+}
+
+func (t deadlineReader) Read(p []byte) (int, error) {
+	if err := t.dl.SetDeadline(time.Now().Add(t.d)); err != nil {
+		return 0, err
+	}
+	n, err := t.r.Read(p)
+	return n, mapTimeout(err)
+}
+
+// deadlineReadSeeker is a deadlineReader over a seekable source, so the copy
+// loop can roll back unwritten bytes.
+type deadlineReadSeeker struct {
+	deadlineReader
+	s Seeker
+}
+
+func (t deadlineReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	return t.s.Seek(offset, whence)
+}
+
+type deadlineWriter struct {
+	w  Writer
+	dl deadliner
+	d  time.Duration
+}
+
+func (t deadlineWriter) Write(p []byte) (int, error) {
+	if err := t.dl.SetDeadline(time.Now().Add(t.d)); err != nil {
+		return 0, err
+	}
+	n, err := t.w.Write(p)
+	return n, mapTimeout(err)
+}
+
+// mapTimeout rewrites deadline-exceeded errors to ErrTimeout.
+func mapTimeout(err error) error {
+	if err == nil || err == ErrTimeout {
+		return err
+	}
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return ErrTimeout
+	}
+	var te interface{ Timeout() bool }
+	if errors.As(err, &te) && te.Timeout() {
+		return ErrTimeout
+	}
+	return err
+}
+
+// CopyBudget is like CopyPolicy but caps the wall-clock time spent retrying:
+// once budget has elapsed since the call started, every OnWouldBlock /
+// OnMore decision returns PolicyReturn, so the copy stops with ErrWouldBlock
+// (or ErrMore) and the bytes written so far.
+//
+// It is CopyPolicy with DeadlinePolicy(policy, time.Now().Add(budget)), a
+// budget without context plumbing. The budget is checked between steps only;
+// a single blocking Read or Write can overrun it. A nil policy is treated as
+// ReturnPolicy.
+func CopyBudget(dst Writer, src Reader, policy SemanticPolicy, budget time.Duration) (written int64, err error) {
+	return CopyPolicy(dst, src, DeadlinePolicy(policy, time.Now().Add(budget)))
+}
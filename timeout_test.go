@@ -0,0 +1,227 @@
+// ©Hayabusa Cloud Co., Ltd. 2025. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package iox_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"code.hybscloud.com/iox"
+)
+
+// gatedReader blocks each Read until a value arrives on gate.
+type gatedReader struct {
+	gate chan []byte
+}
+
+func (r gatedReader) Read(p []byte) (int, error) {
+	b, ok := <-r.gate
+	if !ok {
+		return 0, io.EOF
+	}
+	return copy(p, b), nil
+}
+
+func TestTimeoutReader_SlowReadTimesOut(t *testing.T) {
+	src := gatedReader{gate: make(chan []byte)}
+	r := iox.TimeoutReader(src, 10*time.Millisecond)
+	p := make([]byte, 8)
+	if n, err := r.Read(p); n != 0 || err != iox.ErrTimeout {
+		t.Fatalf("want (0, ErrTimeout) got (%d, %v)", n, err)
+	}
+
+	// The abandoned read completes later and is delivered by the next Read.
+	src.gate <- []byte("late")
+	if n, err := r.Read(p[:2]); n != 2 || err != nil || string(p[:2]) != "la" {
+		t.Fatalf("resume: (%d,%v,%q)", n, err, p[:n])
+	}
+	if n, err := r.Read(p); n != 2 || err != nil || string(p[:2]) != "te" {
+		t.Fatalf("rest: (%d,%v,%q)", n, err, p[:n])
+	}
+	close(src.gate)
+	if n, err := r.Read(p); n != 0 || err != io.EOF {
+		t.Fatalf("eof: (%d,%v)", n, err)
+	}
+}
+
+func TestTimeoutReader_FastReadPassesThrough(t *testing.T) {
+	r := iox.TimeoutReader(&dataThenAlwaysWBReader{data: []byte("fast")}, time.Second)
+	p := make([]byte, 8)
+	if n, err := r.Read(p); n != 4 || err != nil || string(p[:n]) != "fast" {
+		t.Fatalf("data: (%d,%v,%q)", n, err, p[:n])
+	}
+	if n, err := r.Read(p); n != 0 || err != iox.ErrWouldBlock {
+		t.Fatalf("would-block: (%d,%v)", n, err)
+	}
+
+	plain := &plainReader{data: []byte("x")}
+	if got := iox.TimeoutReader(plain, 0); got != iox.Reader(plain) {
+		t.Fatal("d <= 0 should return r")
+	}
+}
+
+// deadlineConn serves chunks, then fails a Read with os.ErrDeadlineExceeded
+// once the script runs out. It records every SetDeadline call.
+type deadlineConn struct {
+	chunks    [][]byte
+	deadlines []time.Time
+}
+
+func (c *deadlineConn) Read(p []byte) (int, error) {
+	if len(c.chunks) == 0 {
+		return 0, os.ErrDeadlineExceeded
+	}
+	n := copy(p, c.chunks[0])
+	c.chunks = c.chunks[1:]
+	return n, nil
+}
+
+func (c *deadlineConn) SetDeadline(t time.Time) error {
+	c.deadlines = append(c.deadlines, t)
+	return nil
+}
+
+func TestCopyDeadline_ReadTimeout(t *testing.T) {
+	src := &deadlineConn{chunks: [][]byte{[]byte("abc"), []byte("de")}}
+	var dst bytes.Buffer
+	n, err := iox.CopyDeadline(&dst, src, time.Second)
+	if n != 5 || err != iox.ErrTimeout || dst.String() != "abcde" {
+		t.Fatalf("n=%d err=%v dst=%q want (5,ErrTimeout,\"abcde\")", n, err, dst.String())
+	}
+	// A clearing probe, one deadline per Read, then cleared on return.
+	if len(src.deadlines) != 5 {
+		t.Fatalf("SetDeadline calls=%d want 5", len(src.deadlines))
+	}
+	if !src.deadlines[0].IsZero() {
+		t.Fatal("probe set a deadline")
+	}
+	for i, d := range src.deadlines[1:4] {
+		if d.IsZero() {
+			t.Fatalf("deadline %d not set", i)
+		}
+	}
+	if !src.deadlines[4].IsZero() {
+		t.Fatal("deadline not cleared")
+	}
+}
+
+func TestCopyDeadline_RegularFileHasNoDeadline(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "copydeadline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err = f.WriteString("file data"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	var dst bytes.Buffer
+	n, err := iox.CopyDeadline(&dst, f, time.Second)
+	if n != 9 || err != nil || dst.String() != "file data" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, dst.String())
+	}
+}
+
+// seekDeadlineConn is a seekable source with working deadlines; clearErr is
+// returned when the deadline is cleared after the probe.
+type seekDeadlineConn struct {
+	workingSeeker
+	calls    int
+	clearErr error
+}
+
+func (c *seekDeadlineConn) SetDeadline(t time.Time) error {
+	c.calls++
+	if t.IsZero() && c.calls > 1 {
+		return c.clearErr
+	}
+	return nil
+}
+
+func TestCopyDeadline_SeekerRollbackAndClearError(t *testing.T) {
+	src := &seekDeadlineConn{workingSeeker: workingSeeker{data: []byte("hello")}}
+	dst := &partialThenWBWriter{k: 2}
+	n, err := iox.CopyDeadline(dst, src, time.Second)
+	if n != 2 || err != iox.ErrWouldBlock {
+		t.Fatalf("partial: n=%d err=%v want (2, ErrWouldBlock)", n, err)
+	}
+	if n, err = iox.CopyDeadline(dst, src, time.Second); n != 3 || err != nil || dst.buf.String() != "hello" {
+		t.Fatalf("resume: n=%d err=%v dst=%q", n, err, dst.buf.String())
+	}
+
+	boom := errors.New("clear failed")
+	src = &seekDeadlineConn{workingSeeker: workingSeeker{data: []byte("x")}, clearErr: boom}
+	var buf bytes.Buffer
+	if n, err = iox.CopyDeadline(&buf, src, time.Second); n != 1 || err != boom {
+		t.Fatalf("clear: n=%d err=%v want (1, boom)", n, err)
+	}
+}
+
+func TestCopyDeadline_NoDeadlineFallsBack(t *testing.T) {
+	var dst bytes.Buffer
+	n, err := iox.CopyDeadline(&dst, bytes.NewReader([]byte("plain")), time.Second)
+	if n != 5 || err != nil || dst.String() != "plain" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, dst.String())
+	}
+}
+
+func TestCopyBudget_StopsAfterBudget(t *testing.T) {
+	const budget = 10 * time.Millisecond
+	var dst bytes.Buffer
+	start := time.Now()
+	n, err := iox.CopyBudget(&dst, &dataThenAlwaysWBReader{data: []byte("ab")}, iox.YieldPolicy{}, budget)
+	elapsed := time.Since(start)
+	if n != 2 || err != iox.ErrWouldBlock || dst.String() != "ab" {
+		t.Fatalf("n=%d err=%v dst=%q", n, err, dst.String())
+	}
+	if elapsed < budget || elapsed > budget+time.Second {
+		t.Fatalf("elapsed=%v, want within [%v, %v]", elapsed, budget, budget+time.Second)
+	}
+}
+
+func TestDeadlineReader_WaitsForData(t *testing.T) {
+	src := &scriptedReader{steps: []scriptStep{
+		{err: iox.ErrWouldBlock}, {err: iox.ErrWouldBlock}, {err: iox.ErrWouldBlock}, {b: []byte("data")},
+	}}
+	var b iox.Backoff
+	waits := 0
+	b.SetSleepFunc(func(time.Duration) { waits++ })
+	r := iox.DeadlineReader(src, &b, time.Second)
+	var p [8]byte
+	n, err := r.Read(p[:])
+	if n != 4 || err != nil || string(p[:n]) != "data" {
+		t.Fatalf("read=(%d,%v,%q)", n, err, p[:n])
+	}
+	if waits != 3 {
+		t.Fatalf("waits=%d want 3", waits)
+	}
+	if n, err = r.Read(p[:]); n != 0 || err != io.EOF {
+		t.Fatalf("eof=(%d,%v)", n, err)
+	}
+}
+
+func TestDeadlineReader_TimesOut(t *testing.T) {
+	const budget = 5 * time.Millisecond
+	var b iox.Backoff
+	b.SetBase(100 * time.Microsecond)
+	b.SetMax(time.Millisecond)
+	r := iox.DeadlineReader(errReaderAlwaysWB{}, &b, budget)
+	var p [8]byte
+	start := time.Now()
+	n, err := r.Read(p[:])
+	elapsed := time.Since(start)
+	if n != 0 || err != iox.ErrTimeout {
+		t.Fatalf("want (0, ErrTimeout) got (%d, %v)", n, err)
+	}
+	if elapsed < budget || elapsed > budget+time.Second {
+		t.Fatalf("elapsed=%v, want within [%v, %v]", elapsed, budget, budget+time.Second)
+	}
+}